@@ -0,0 +1,386 @@
+package svgparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// Rect is a typed view of the geometry of a <rect> element. RX and RY are
+// nil when they are missing or auto.
+type Rect struct {
+	X, Y, Width, Height utils.Length
+	RX, RY              *utils.Length
+}
+
+// Circle is a typed view of the geometry of a <circle> element.
+type Circle struct {
+	CX, CY, R utils.Length
+}
+
+// Ellipse is a typed view of the geometry of an <ellipse> element. RX and RY
+// are nil when they are missing or auto.
+type Ellipse struct {
+	CX, CY utils.Length
+	RX, RY *utils.Length
+}
+
+// Line is a typed view of the geometry of a <line> element.
+type Line struct {
+	X1, Y1, X2, Y2 utils.Length
+}
+
+// Image is a typed view of an <image> element. Href is taken from href or,
+// if missing, from xlink:href.
+type Image struct {
+	X, Y, Width, Height utils.Length
+	Href                string
+	PreserveAspectRatio string
+}
+
+// Text is a typed view of the positioning attributes and the character data
+// of a <text> or <tspan> element.
+type Text struct {
+	X, Y, DX, DY []utils.Length
+	Rotate       []float64
+	Content      string
+}
+
+// expectName returns an error unless the element has one of the names.
+func (e *Element) expectName(names ...string) error {
+	for _, name := range names {
+		if e.Name == name {
+			return nil
+		}
+	}
+	return ValidationError{fmt.Sprintf("%s is not a %s", e.Name, strings.Join(names, " or "))}
+}
+
+// length returns the length of an attribute, zero if it is missing, or an
+// error if it is invalid or negative where negative values are not allowed.
+func (e *Element) length(name string, negative bool) (utils.Length, error) {
+	v, ok := e.Attributes[name]
+	if !ok {
+		return utils.Length{}, nil
+	}
+	l, err := utils.LengthParser(v)
+	if err != nil {
+		return utils.Length{}, ValidationError{fmt.Sprintf("%s: invalid %s %q", e.Name, name, v)}
+	}
+	if l.Value < 0 && !negative {
+		return utils.Length{}, ValidationError{fmt.Sprintf("%s: negative %s %q", e.Name, name, v)}
+	}
+	return l, nil
+}
+
+// lengths returns the lengths of attributes, stopping at the first error.
+func (e *Element) lengths(negative bool, names ...string) ([]utils.Length, error) {
+	lengths := make([]utils.Length, len(names))
+	for i, name := range names {
+		l, err := e.length(name, negative)
+		if err != nil {
+			return nil, err
+		}
+		lengths[i] = l
+	}
+	return lengths, nil
+}
+
+// optionalLength returns the non-negative length of an attribute, or nil if
+// it is missing or auto.
+func (e *Element) optionalLength(name string) (*utils.Length, error) {
+	if v, ok := e.Attributes[name]; !ok || strings.TrimSpace(v) == "auto" {
+		return nil, nil
+	}
+	l, err := e.length(name, false)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// lengthList returns the list of lengths of an attribute, or nil if it is
+// missing or empty.
+func (e *Element) lengthList(name string) ([]utils.Length, error) {
+	lengths, err := utils.LengthListParser(e.Attributes[name])
+	if err != nil {
+		return nil, ValidationError{fmt.Sprintf("%s: invalid %s %q", e.Name, name, e.Attributes[name])}
+	}
+	return lengths, nil
+}
+
+// setLength writes a length attribute.
+func (e *Element) setLength(name string, l utils.Length) {
+	e.Attributes[name] = l.String()
+}
+
+// setOptionalLength writes a length attribute, or removes it if l is nil.
+func (e *Element) setOptionalLength(name string, l *utils.Length) {
+	if l == nil {
+		delete(e.Attributes, name)
+		return
+	}
+	e.setLength(name, *l)
+}
+
+// setLengthList writes a list of lengths, or removes the attribute if the
+// list is empty.
+func (e *Element) setLengthList(name string, lengths []utils.Length) {
+	if len(lengths) == 0 {
+		delete(e.Attributes, name)
+		return
+	}
+	values := make([]string, len(lengths))
+	for i, l := range lengths {
+		values[i] = l.String()
+	}
+	e.Attributes[name] = strings.Join(values, " ")
+}
+
+// AsRect returns the geometry of a <rect> element. Invalid values and
+// negative sizes give an error.
+func (e *Element) AsRect() (Rect, error) {
+	if err := e.expectName("rect"); err != nil {
+		return Rect{}, err
+	}
+	position, err := e.lengths(true, "x", "y")
+	if err != nil {
+		return Rect{}, err
+	}
+	size, err := e.lengths(false, "width", "height")
+	if err != nil {
+		return Rect{}, err
+	}
+	r := Rect{X: position[0], Y: position[1], Width: size[0], Height: size[1]}
+	if r.RX, err = e.optionalLength("rx"); err != nil {
+		return Rect{}, err
+	}
+	if r.RY, err = e.optionalLength("ry"); err != nil {
+		return Rect{}, err
+	}
+	return r, nil
+}
+
+// SetRect writes the geometry of a <rect> element.
+func (e *Element) SetRect(r Rect) error {
+	if err := e.expectName("rect"); err != nil {
+		return err
+	}
+	e.setLength("x", r.X)
+	e.setLength("y", r.Y)
+	e.setLength("width", r.Width)
+	e.setLength("height", r.Height)
+	e.setOptionalLength("rx", r.RX)
+	e.setOptionalLength("ry", r.RY)
+	return nil
+}
+
+// AsCircle returns the geometry of a <circle> element. Invalid values and a
+// negative radius give an error.
+func (e *Element) AsCircle() (Circle, error) {
+	if err := e.expectName("circle"); err != nil {
+		return Circle{}, err
+	}
+	center, err := e.lengths(true, "cx", "cy")
+	if err != nil {
+		return Circle{}, err
+	}
+	r, err := e.length("r", false)
+	if err != nil {
+		return Circle{}, err
+	}
+	return Circle{center[0], center[1], r}, nil
+}
+
+// SetCircle writes the geometry of a <circle> element.
+func (e *Element) SetCircle(c Circle) error {
+	if err := e.expectName("circle"); err != nil {
+		return err
+	}
+	e.setLength("cx", c.CX)
+	e.setLength("cy", c.CY)
+	e.setLength("r", c.R)
+	return nil
+}
+
+// AsEllipse returns the geometry of an <ellipse> element. Invalid values and
+// negative radii give an error.
+func (e *Element) AsEllipse() (Ellipse, error) {
+	if err := e.expectName("ellipse"); err != nil {
+		return Ellipse{}, err
+	}
+	center, err := e.lengths(true, "cx", "cy")
+	if err != nil {
+		return Ellipse{}, err
+	}
+	ellipse := Ellipse{CX: center[0], CY: center[1]}
+	if ellipse.RX, err = e.optionalLength("rx"); err != nil {
+		return Ellipse{}, err
+	}
+	if ellipse.RY, err = e.optionalLength("ry"); err != nil {
+		return Ellipse{}, err
+	}
+	return ellipse, nil
+}
+
+// SetEllipse writes the geometry of an <ellipse> element.
+func (e *Element) SetEllipse(ellipse Ellipse) error {
+	if err := e.expectName("ellipse"); err != nil {
+		return err
+	}
+	e.setLength("cx", ellipse.CX)
+	e.setLength("cy", ellipse.CY)
+	e.setOptionalLength("rx", ellipse.RX)
+	e.setOptionalLength("ry", ellipse.RY)
+	return nil
+}
+
+// AsLine returns the geometry of a <line> element.
+func (e *Element) AsLine() (Line, error) {
+	if err := e.expectName("line"); err != nil {
+		return Line{}, err
+	}
+	l, err := e.lengths(true, "x1", "y1", "x2", "y2")
+	if err != nil {
+		return Line{}, err
+	}
+	return Line{l[0], l[1], l[2], l[3]}, nil
+}
+
+// SetLine writes the geometry of a <line> element.
+func (e *Element) SetLine(l Line) error {
+	if err := e.expectName("line"); err != nil {
+		return err
+	}
+	e.setLength("x1", l.X1)
+	e.setLength("y1", l.Y1)
+	e.setLength("x2", l.X2)
+	e.setLength("y2", l.Y2)
+	return nil
+}
+
+// Points returns the points of a <polyline> or <polygon> element. Invalid
+// numbers give an error, and the odd last coordinate is ignored, like
+// renderers do.
+func (e *Element) Points() ([]utils.Point, error) {
+	if err := e.expectName("polyline", "polygon"); err != nil {
+		return nil, err
+	}
+	numbers, err := utils.NumberListParser(e.Attributes["points"])
+	if err != nil {
+		return nil, ValidationError{fmt.Sprintf("%s: invalid points %q", e.Name, e.Attributes["points"])}
+	}
+	points := make([]utils.Point, 0, len(numbers)/2)
+	for i := 0; i+1 < len(numbers); i += 2 {
+		points = append(points, utils.Point{X: numbers[i], Y: numbers[i+1]})
+	}
+	return points, nil
+}
+
+// SetPoints writes the points of a <polyline> or <polygon> element.
+func (e *Element) SetPoints(points []utils.Point) error {
+	if err := e.expectName("polyline", "polygon"); err != nil {
+		return err
+	}
+	values := make([]string, len(points))
+	for i, p := range points {
+		values[i] = formatNumber(p.X) + "," + formatNumber(p.Y)
+	}
+	e.Attributes["points"] = strings.Join(values, " ")
+	return nil
+}
+
+// AsImage returns an <image> element. Invalid values and negative sizes
+// give an error.
+func (e *Element) AsImage() (Image, error) {
+	if err := e.expectName("image"); err != nil {
+		return Image{}, err
+	}
+	position, err := e.lengths(true, "x", "y")
+	if err != nil {
+		return Image{}, err
+	}
+	size, err := e.lengths(false, "width", "height")
+	if err != nil {
+		return Image{}, err
+	}
+	href, ok := e.Attributes["href"]
+	if !ok {
+		href = e.Attributes["xlink:href"]
+	}
+	return Image{position[0], position[1], size[0], size[1], href, e.Attributes["preserveAspectRatio"]}, nil
+}
+
+// SetImage writes an <image> element. The reference is written to the
+// attribute it was read from, href for new references.
+func (e *Element) SetImage(image Image) error {
+	if err := e.expectName("image"); err != nil {
+		return err
+	}
+	e.setLength("x", image.X)
+	e.setLength("y", image.Y)
+	e.setLength("width", image.Width)
+	e.setLength("height", image.Height)
+	if _, ok := e.Attributes["href"]; !ok {
+		if _, ok := e.Attributes["xlink:href"]; ok {
+			e.Attributes["xlink:href"] = image.Href
+		} else {
+			e.Attributes["href"] = image.Href
+		}
+	} else {
+		e.Attributes["href"] = image.Href
+	}
+	if image.PreserveAspectRatio != "" {
+		e.Attributes["preserveAspectRatio"] = image.PreserveAspectRatio
+	} else {
+		delete(e.Attributes, "preserveAspectRatio")
+	}
+	return nil
+}
+
+// AsText returns the positioning attributes and the character data of a
+// <text> or <tspan> element.
+func (e *Element) AsText() (Text, error) {
+	if err := e.expectName("text", "tspan"); err != nil {
+		return Text{}, err
+	}
+	text := Text{Content: e.Content}
+	lists := []*[]utils.Length{&text.X, &text.Y, &text.DX, &text.DY}
+	for i, name := range []string{"x", "y", "dx", "dy"} {
+		lengths, err := e.lengthList(name)
+		if err != nil {
+			return Text{}, err
+		}
+		*lists[i] = lengths
+	}
+	rotate, err := utils.NumberListParser(e.Attributes["rotate"])
+	if err != nil {
+		return Text{}, ValidationError{fmt.Sprintf("%s: invalid rotate %q", e.Name, e.Attributes["rotate"])}
+	}
+	text.Rotate = rotate
+	return text, nil
+}
+
+// SetText writes the positioning attributes and the character data of a
+// <text> or <tspan> element. Empty lists remove their attribute.
+func (e *Element) SetText(text Text) error {
+	if err := e.expectName("text", "tspan"); err != nil {
+		return err
+	}
+	e.setLengthList("x", text.X)
+	e.setLengthList("y", text.Y)
+	e.setLengthList("dx", text.DX)
+	e.setLengthList("dy", text.DY)
+	if len(text.Rotate) == 0 {
+		delete(e.Attributes, "rotate")
+	} else {
+		values := make([]string, len(text.Rotate))
+		for i, r := range text.Rotate {
+			values[i] = formatNumber(r)
+		}
+		e.Attributes["rotate"] = strings.Join(values, " ")
+	}
+	e.Content = text.Content
+	return nil
+}
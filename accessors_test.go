@@ -0,0 +1,174 @@
+package svgparser_test
+
+import (
+	"reflect"
+	"testing"
+
+	svgparser "github.com/chikamim/svgparser"
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestAsRect(t *testing.T) {
+	e := &svgparser.Element{Name: "rect", Attributes: map[string]string{
+		"x": "1", "y": "-2.5px", "width": "50%", "height": "3em", "rx": "auto", "ry": "4",
+	}}
+	r, err := e.AsRect()
+	if err != nil {
+		t.Fatalf("AsRect failed: %v\n", err)
+	}
+	expected := svgparser.Rect{
+		X: utils.Length{Value: 1}, Y: utils.Length{Value: -2.5, Unit: "px"},
+		Width: utils.Length{Value: 50, Unit: "%"}, Height: utils.Length{Value: 3, Unit: "em"},
+		RY: &utils.Length{Value: 4},
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("AsRect: expected %+v, actual %+v\n", expected, r)
+	}
+
+	r.Width = utils.Length{Value: 20}
+	r.RY = nil
+	if err := e.SetRect(r); err != nil {
+		t.Fatalf("SetRect failed: %v\n", err)
+	}
+	if e.Attributes["width"] != "20" || e.Attributes["y"] != "-2.5px" {
+		t.Errorf("SetRect: unexpected attributes %v\n", e.Attributes)
+	}
+	if _, ok := e.Attributes["ry"]; ok {
+		t.Errorf("SetRect: expected ry to be removed, actual %v\n", e.Attributes)
+	}
+}
+
+func TestAsShapes(t *testing.T) {
+	circle := &svgparser.Element{Name: "circle", Attributes: map[string]string{"cx": "5", "cy": "6", "r": "2mm"}}
+	c, err := circle.AsCircle()
+	expectedCircle := svgparser.Circle{CX: utils.Length{Value: 5}, CY: utils.Length{Value: 6}, R: utils.Length{Value: 2, Unit: "mm"}}
+	if err != nil || c != expectedCircle {
+		t.Errorf("AsCircle: expected %+v, actual %+v (%v)\n", expectedCircle, c, err)
+	}
+
+	ellipse := &svgparser.Element{Name: "ellipse", Attributes: map[string]string{"rx": "3"}}
+	el, err := ellipse.AsEllipse()
+	if err != nil || el.RX == nil || *el.RX != (utils.Length{Value: 3}) || el.RY != nil {
+		t.Errorf("AsEllipse: unexpected %+v (%v)\n", el, err)
+	}
+
+	line := &svgparser.Element{Name: "line", Attributes: map[string]string{"x1": "1", "y2": "4"}}
+	l, err := line.AsLine()
+	expectedLine := svgparser.Line{X1: utils.Length{Value: 1}, Y2: utils.Length{Value: 4}}
+	if err != nil || l != expectedLine {
+		t.Errorf("AsLine: expected %+v, actual %+v (%v)\n", expectedLine, l, err)
+	}
+	l.X2 = utils.Length{Value: 7, Unit: "pt"}
+	if err := line.SetLine(l); err != nil || line.Attributes["x2"] != "7pt" {
+		t.Errorf("SetLine: unexpected attributes %v (%v)\n", line.Attributes, err)
+	}
+
+	image := &svgparser.Element{Name: "image", Attributes: map[string]string{"width": "10", "height": "20", "xlink:href": "a.png"}}
+	i, err := image.AsImage()
+	if err != nil || i.Href != "a.png" || i.Width != (utils.Length{Value: 10}) {
+		t.Errorf("AsImage: unexpected %+v (%v)\n", i, err)
+	}
+	i.Href = "b.png"
+	if err := image.SetImage(i); err != nil || image.Attributes["xlink:href"] != "b.png" {
+		t.Errorf("SetImage: unexpected attributes %v (%v)\n", image.Attributes, err)
+	}
+}
+
+func TestAsText(t *testing.T) {
+	e := &svgparser.Element{Name: "text", Content: "Hello", Attributes: map[string]string{
+		"x": "1 2,3", "dy": "1em", "rotate": "0 45",
+	}}
+	text, err := e.AsText()
+	if err != nil {
+		t.Fatalf("AsText failed: %v\n", err)
+	}
+	expected := svgparser.Text{
+		X:       []utils.Length{{Value: 1}, {Value: 2}, {Value: 3}},
+		DY:      []utils.Length{{Value: 1, Unit: "em"}},
+		Rotate:  []float64{0, 45},
+		Content: "Hello",
+	}
+	if !reflect.DeepEqual(text, expected) {
+		t.Errorf("AsText: expected %+v, actual %+v\n", expected, text)
+	}
+
+	text.X = nil
+	text.Rotate = []float64{90}
+	if err := e.SetText(text); err != nil {
+		t.Fatalf("SetText failed: %v\n", err)
+	}
+	if _, ok := e.Attributes["x"]; ok || e.Attributes["rotate"] != "90" || e.Attributes["dy"] != "1em" {
+		t.Errorf("SetText: unexpected attributes %v\n", e.Attributes)
+	}
+}
+
+func TestPoints(t *testing.T) {
+	e := &svgparser.Element{Name: "polygon", Attributes: map[string]string{"points": "0,0 10,0\n10 10.5"}}
+	points, err := e.Points()
+	expected := []utils.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10.5}}
+	if err != nil || !reflect.DeepEqual(points, expected) {
+		t.Errorf("Points: expected %v, actual %v (%v)\n", expected, points, err)
+	}
+
+	if err := e.SetPoints(expected[:2]); err != nil || e.Attributes["points"] != "0,0 10,0" {
+		t.Errorf("SetPoints: unexpected attributes %v (%v)\n", e.Attributes, err)
+	}
+
+	// compact coordinates, where the odd last one is ignored like in Path
+	e.Attributes["points"] = "10-5 20-5 30"
+	points, err = e.Points()
+	expected = []utils.Point{{X: 10, Y: -5}, {X: 20, Y: -5}}
+	if err != nil || !reflect.DeepEqual(points, expected) {
+		t.Errorf("Points: expected %v, actual %v (%v)\n", expected, points, err)
+	}
+}
+
+func TestAccessorErrors(t *testing.T) {
+	testCases := []struct {
+		element  *svgparser.Element
+		accessor func(e *svgparser.Element) error
+		expected string
+	}{
+		{
+			&svgparser.Element{Name: "rect", Attributes: map[string]string{"width": "ten"}},
+			func(e *svgparser.Element) error { _, err := e.AsRect(); return err },
+			`rect: invalid width "ten"`,
+		},
+		{
+			&svgparser.Element{Name: "rect", Attributes: map[string]string{"rx": "-1"}},
+			func(e *svgparser.Element) error { _, err := e.AsRect(); return err },
+			`rect: negative rx "-1"`,
+		},
+		{
+			&svgparser.Element{Name: "circle", Attributes: map[string]string{"r": "2furlongs"}},
+			func(e *svgparser.Element) error { _, err := e.AsCircle(); return err },
+			`circle: invalid r "2furlongs"`,
+		},
+		{
+			&svgparser.Element{Name: "circle", Attributes: map[string]string{}},
+			func(e *svgparser.Element) error { _, err := e.AsRect(); return err },
+			"circle is not a rect",
+		},
+		{
+			&svgparser.Element{Name: "polygon", Attributes: map[string]string{"points": "0,0 a,b"}},
+			func(e *svgparser.Element) error { _, err := e.Points(); return err },
+			`polygon: invalid points "0,0 a,b"`,
+		},
+		{
+			&svgparser.Element{Name: "rect", Attributes: map[string]string{}},
+			func(e *svgparser.Element) error { _, err := e.Points(); return err },
+			"rect is not a polyline or polygon",
+		},
+		{
+			&svgparser.Element{Name: "text", Attributes: map[string]string{"rotate": "up"}},
+			func(e *svgparser.Element) error { _, err := e.AsText(); return err },
+			`text: invalid rotate "up"`,
+		},
+	}
+
+	for _, test := range testCases {
+		if err := test.accessor(test.element); err == nil || err.Error() != test.expected {
+			t.Errorf("%s %v: expected error %q, actual %v\n", test.element.Name, test.element.Attributes, test.expected, err)
+		}
+	}
+}
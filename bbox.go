@@ -0,0 +1,313 @@
+package svgparser
+
+import (
+	"math"
+	"strings"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// BoxKind selects what a bounding box covers.
+type BoxKind int
+
+// FillBox covers the geometry of shapes, StrokeBox adds the stroke and
+// VisualBox adds the stroke and markers.
+const (
+	FillBox BoxKind = iota
+	StrokeBox
+	VisualBox
+)
+
+// boxer collects the bounding box of elements.
+type boxer struct {
+	kind     BoxKind
+	styles   *styleResolver
+	visiting map[*Element]bool
+}
+
+// BBox returns the bounding box of a shape, image, group, <svg> or <use>
+// element in its user space, which is the space its geometry is given in,
+// without its own transform and, for <svg>, in the coordinates of its
+// viewBox. The transforms of descendants and the content referenced by
+// <use> are taken into account, while non-rendered elements such as <defs>
+// and content with display:none are excluded. Lengths with units and
+// percentages are resolved into user units at 96 DPI, and styles are
+// computed with the cascade, including the document's <style> elements. The
+// stroke is approximated by half the stroke width in every direction, which
+// is exact for round joins and for the corners of axis aligned shapes. Text
+// is not measured. An empty box is returned for elements without geometry,
+// and an error for invalid geometry.
+func BBox(e *Element, kind BoxKind) (utils.Box, error) {
+	styles := e.defaultStyles()
+	if styles.property(e, "display") == "none" {
+		return utils.EmptyBox(), nil
+	}
+	b := &boxer{kind, styles, map[*Element]bool{}}
+	return b.content(e, utils.Identity())
+}
+
+// element returns the bounding box of e, where m maps the user space of e's
+// parent to the space of the box.
+func (b *boxer) element(e *Element, m utils.Matrix) (utils.Box, error) {
+	if nonRenderedElements[e.Name] {
+		return utils.EmptyBox(), nil
+	}
+	if b.styles.property(e, "display") == "none" {
+		return utils.EmptyBox(), nil
+	}
+	t, err := e.Transform()
+	if err != nil {
+		return utils.Box{}, err
+	}
+	m = m.Multiply(t)
+	if e.Name == "svg" {
+		m = m.Multiply(e.viewportTransform(b.styles))
+	}
+	return b.content(e, m)
+}
+
+// children returns the union of the bounding boxes of the children of e.
+func (b *boxer) children(e *Element, m utils.Matrix) (utils.Box, error) {
+	box := utils.EmptyBox()
+	for _, child := range e.Children {
+		c, err := b.element(child, m)
+		if err != nil {
+			return utils.Box{}, err
+		}
+		box = box.Union(c)
+	}
+	return box, nil
+}
+
+// content returns the bounding box of the geometry and the children of e,
+// where m maps the user space of e to the space of the box.
+func (b *boxer) content(e *Element, m utils.Matrix) (utils.Box, error) {
+	switch e.Name {
+	case "svg", "g", "a", "switch":
+		return b.children(e, m)
+	case "use":
+		target := e.root().FindID(e.hrefID())
+		if target == nil || b.visiting[target] {
+			return utils.EmptyBox(), nil
+		}
+		b.visiting[target] = true
+		defer delete(b.visiting, target)
+		offset, err := e.useOffset(b.styles)
+		if err != nil {
+			return utils.Box{}, err
+		}
+		m = m.Multiply(offset)
+		if target.Name != "symbol" {
+			return b.element(target, m)
+		}
+		t, err := target.Transform()
+		if err != nil {
+			return utils.Box{}, err
+		}
+		return b.children(target, m.Multiply(t).Multiply(symbolTransform(b.styles, target, e)))
+	case "image":
+		box, err := e.imageBox(b.styles)
+		if err != nil || box.IsEmpty() {
+			return box, err
+		}
+		return box.Transform(m), nil
+	}
+	if _, ok := shapeAttributes[e.Name]; ok || e.Name == "path" {
+		return b.shape(e, m)
+	}
+	return utils.EmptyBox(), nil
+}
+
+// imageBox returns the box of an image in its user space, which is empty if
+// the image has no size, or an error if its geometry is invalid.
+func (e *Element) imageBox(r *styleResolver) (utils.Box, error) {
+	n, err := e.geometryNumbers(r, true, "x", "y")
+	if err != nil {
+		return utils.Box{}, err
+	}
+	size, err := e.geometryNumbers(r, false, "width", "height")
+	if err != nil {
+		return utils.Box{}, err
+	}
+	if size[0] == 0 || size[1] == 0 {
+		return utils.EmptyBox(), nil
+	}
+	return utils.Box{Min: utils.Point{X: n[0], Y: n[1]}, Max: utils.Point{X: n[0] + size[0], Y: n[1] + size[1]}}, nil
+}
+
+// shape returns the bounding box of a path or basic shape.
+func (b *boxer) shape(e *Element, m utils.Matrix) (utils.Box, error) {
+	path, err := e.path(b.styles)
+	if err != nil {
+		return utils.Box{}, err
+	}
+	box := path.Transform(m).Bounds()
+	if b.kind == FillBox || box.IsEmpty() {
+		return box, nil
+	}
+	if s, ok := b.styles.paintedStroke(e); ok {
+		// a circle of radius half the stroke width becomes an ellipse with
+		// these extents
+		hx, hy := s.Width/2*math.Hypot(m.A, m.C), s.Width/2*math.Hypot(m.B, m.D)
+		box = utils.Box{Min: utils.Point{X: box.Min.X - hx, Y: box.Min.Y - hy}, Max: utils.Point{X: box.Max.X + hx, Y: box.Max.Y + hy}}
+	}
+	if b.kind == VisualBox {
+		markers, err := b.markers(e, path, m)
+		if err != nil {
+			return utils.Box{}, err
+		}
+		box = box.Union(markers)
+	}
+	return box, nil
+}
+
+// markerVertex is a vertex of a path at which markers are drawn, with the
+// directions of the path into and out of it. Missing directions are zero.
+type markerVertex struct {
+	point, in, out utils.Point
+}
+
+// markerVertices returns the vertices of the path: the start of every
+// subpath and the end of every segment.
+func markerVertices(path *utils.Path) []markerVertex {
+	var vertices []markerVertex
+	for _, c := range path.Contours() {
+		n := len(c.Segments)
+		start := markerVertex{point: c.Start}
+		if n > 0 {
+			start.out = c.Segments[0].Tangent(0)
+			if c.Closed {
+				start.in = c.Segments[n-1].Tangent(1)
+			}
+		}
+		vertices = append(vertices, start)
+		for i, s := range c.Segments {
+			v := markerVertex{point: s.End(), in: s.Tangent(1)}
+			if i < n-1 {
+				v.out = c.Segments[i+1].Tangent(0)
+			} else if c.Closed {
+				v.out = c.Segments[0].Tangent(0)
+			}
+			vertices = append(vertices, v)
+		}
+	}
+	return vertices
+}
+
+// angle returns the direction in degrees of a marker with orient auto at the
+// vertex, which bisects the directions into and out of it.
+func (v markerVertex) angle() float64 {
+	d := utils.Point{X: v.in.X + v.out.X, Y: v.in.Y + v.out.Y}
+	if d.X == 0 && d.Y == 0 {
+		d = v.out
+		if d.X == 0 && d.Y == 0 {
+			d = v.in
+		}
+	}
+	return math.Atan2(d.Y, d.X) * 180 / math.Pi
+}
+
+// markerRef returns the refX or refY of a marker in its user space, or an
+// error if it is invalid. The keywords left, center and right, and top,
+// center and bottom, refer to the viewBox of the marker, or to its size
+// without one.
+func markerRef(r *styleResolver, marker *Element, name string) (float64, error) {
+	start, size := 0.0, 0.0
+	if vb, ok := marker.viewBox(); ok {
+		start, size = vb.X, vb.Width
+		if name == "refY" {
+			start, size = vb.Y, vb.Height
+		}
+	} else if name == "refX" {
+		size = marker.attributeLength(r, "markerWidth", 3)
+	} else {
+		size = marker.attributeLength(r, "markerHeight", 3)
+	}
+	switch strings.TrimSpace(marker.Attributes[name]) {
+	case "left", "top":
+		return start, nil
+	case "center":
+		return start + size/2, nil
+	case "right", "bottom":
+		return start + size, nil
+	}
+	return marker.geometryNumber(r, name, 0, true)
+}
+
+// markerTransform returns the transform which maps the user space of the
+// marker to the user space of the shape for a marker at the vertex.
+func markerTransform(r *styleResolver, marker *Element, v markerVertex, start bool, strokeWidth float64) (utils.Matrix, error) {
+	viewBox, err := marker.viewBoxTransform(r)
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	angle := 0.0
+	switch orient := strings.TrimSpace(marker.Attributes["orient"]); orient {
+	case "auto":
+		angle = v.angle()
+	case "auto-start-reverse":
+		angle = v.angle()
+		if start {
+			angle += 180
+		}
+	default:
+		// invalid angles are the initial value 0
+		angle, _ = utils.AngleParser(orient)
+	}
+	scale := strokeWidth
+	if marker.Attributes["markerUnits"] == "userSpaceOnUse" {
+		scale = 1
+	}
+	refX, err := markerRef(r, marker, "refX")
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	refY, err := markerRef(r, marker, "refY")
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	ref := viewBox.Apply(utils.Point{X: refX, Y: refY})
+	return utils.Translate(v.point.X, v.point.Y).
+		Multiply(utils.Rotate(angle)).
+		Multiply(utils.Scale(scale, scale)).
+		Multiply(utils.Translate(-ref.X, -ref.Y)).
+		Multiply(viewBox), nil
+}
+
+// markers returns the bounding box of the markers of a shape, whose path is
+// mapped to the space of the box by m. Clipping at the marker viewport is
+// not considered.
+func (b *boxer) markers(e *Element, path *utils.Path, m utils.Matrix) (utils.Box, error) {
+	box := utils.EmptyBox()
+	strokeWidth := b.styles.stroke(e).Width
+	vertices := markerVertices(path)
+	for i, v := range vertices {
+		property := "marker-mid"
+		switch i {
+		case 0:
+			property = "marker-start"
+		case len(vertices) - 1:
+			property = "marker-end"
+		}
+		match := urlReference.FindStringSubmatch(b.styles.property(e, property))
+		if match == nil {
+			continue
+		}
+		marker := e.root().FindID(match[1])
+		if marker == nil || marker.Name != "marker" || b.visiting[marker] {
+			continue
+		}
+		t, err := markerTransform(b.styles, marker, v, i == 0, strokeWidth)
+		if err != nil {
+			return utils.Box{}, err
+		}
+		b.visiting[marker] = true
+		c, err := b.children(marker, m.Multiply(t))
+		delete(b.visiting, marker)
+		if err != nil {
+			return utils.Box{}, err
+		}
+		box = box.Union(c)
+	}
+	return box, nil
+}
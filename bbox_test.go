@@ -0,0 +1,111 @@
+package svgparser_test
+
+import (
+	"math"
+	"testing"
+
+	svgparser "github.com/chikamim/svgparser"
+	"github.com/chikamim/svgparser/utils"
+)
+
+func closeBoxes(a, b utils.Box) bool {
+	return closePoints(a.Min, b.Min) && closePoints(a.Max, b.Max)
+}
+
+func box(x0, y0, x1, y1 float64) utils.Box {
+	return utils.Box{Min: utils.Point{X: x0, Y: y0}, Max: utils.Point{X: x1, Y: y1}}
+}
+
+func TestBBox(t *testing.T) {
+	svg := `
+		<svg width="100" height="100" viewBox="0 0 50 50">
+			<defs>
+				<symbol id="icon" viewBox="0 0 10 10"><circle cx="5" cy="5" r="5" /></symbol>
+				<marker id="arrow" orient="auto" markerUnits="strokeWidth"><rect width="2" height="2" /></marker>
+			</defs>
+			<g id="rotated"><rect width="10" height="10" transform="rotate(45)" /></g>
+			<rect id="stroked" x="20" width="10" height="10" stroke="black" stroke-width="2" />
+			<g id="hidden">
+				<rect width="1" height="1" />
+				<rect x="100" width="1" height="1" style="display: none" />
+			</g>
+			<use id="use" href="#icon" x="5" width="20" height="20" />
+			<path id="right" d="M0 0 L10 0" stroke="black" stroke-width="2" marker-end="url(#arrow)" />
+			<path id="down" d="M0 0 V10" stroke-width="2" marker-end="url(#arrow)" />
+			<g id="none" style="display:none"><rect width="1" height="1" /></g>
+			<text id="text" x="10" y="10">Hello</text>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	d := 10 / math.Sqrt2
+	testCases := []struct {
+		id       string
+		kind     svgparser.BoxKind
+		expected utils.Box
+	}{
+		{"rotated", svgparser.FillBox, box(-d, 0, d, 2*d)},
+		{"stroked", svgparser.FillBox, box(20, 0, 30, 10)},
+		{"stroked", svgparser.StrokeBox, box(19, -1, 31, 11)},
+		{"hidden", svgparser.FillBox, box(0, 0, 1, 1)},
+		{"use", svgparser.FillBox, box(5, 0, 25, 20)},
+		{"right", svgparser.StrokeBox, box(-1, -1, 11, 1)},
+		{"right", svgparser.VisualBox, box(-1, -1, 14, 4)},
+		{"down", svgparser.VisualBox, box(-4, 0, 0, 14)},
+	}
+
+	for _, test := range testCases {
+		actual, err := svgparser.BBox(element.FindID(test.id), test.kind)
+		if err != nil {
+			t.Errorf("BBox %s failed: %v\n", test.id, err)
+		} else if !closeBoxes(actual, test.expected) {
+			t.Errorf("BBox %s %v: expected %v, actual %v\n", test.id, test.kind, test.expected, actual)
+		}
+	}
+
+	for _, id := range []string{"none", "text"} {
+		if actual, _ := svgparser.BBox(element.FindID(id), svgparser.VisualBox); !actual.IsEmpty() {
+			t.Errorf("BBox %s: expected empty box, actual %v\n", id, actual)
+		}
+	}
+
+	root, err := svgparser.BBox(element, svgparser.FillBox)
+	if err != nil || !closeBoxes(root, box(-d, 0, 30, 20)) {
+		t.Errorf("BBox svg: expected %v, actual %v (%v)\n", box(-d, 0, 30, 20), root, err)
+	}
+}
+
+func TestBBoxLengthsAndStyles(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<style>.thick { stroke: black; stroke-width: 4 }</style>
+			<defs>
+				<rect id="square" width="1" height="1" />
+				<marker id="tip" orient="0.25turn" refX="1px" markerUnits="userSpaceOnUse"><rect width="2" height="2" /></marker>
+			</defs>
+			<image id="image" x="1in" width="50%" height="10%" />
+			<use id="offset" href="#square" x="10%" />
+			<rect id="thick" class="thick" width="10" height="10" />
+			<path id="turned" d="M0 0 H10" marker-end="url(#tip)" />
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	testCases := []struct {
+		id       string
+		kind     svgparser.BoxKind
+		expected utils.Box
+	}{
+		{"image", svgparser.FillBox, box(96, 0, 146, 10)},
+		{"offset", svgparser.FillBox, box(10, 0, 11, 1)},
+		{"thick", svgparser.StrokeBox, box(-2, -2, 12, 12)},
+		{"turned", svgparser.VisualBox, box(0, -1, 10, 1)},
+	}
+
+	for _, test := range testCases {
+		actual, err := svgparser.BBox(element.FindID(test.id), test.kind)
+		if err != nil {
+			t.Errorf("BBox %s failed: %v\n", test.id, err)
+		} else if !closeBoxes(actual, test.expected) {
+			t.Errorf("BBox %s %v: expected %v, actual %v\n", test.id, test.kind, test.expected, actual)
+		}
+	}
+}
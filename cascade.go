@@ -0,0 +1,303 @@
+package svgparser
+
+import (
+	"strings"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// initialValues lists the properties which the user agent gives a value to
+// on every element, with their initial values.
+var initialValues = map[string]string{
+	"fill": "black", "fill-opacity": "1", "fill-rule": "nonzero",
+	"stroke": "none", "stroke-width": "1", "stroke-opacity": "1",
+	"stroke-linecap": "butt", "stroke-linejoin": "miter", "stroke-miterlimit": "4",
+	"stroke-dasharray": "none", "stroke-dashoffset": "0",
+	"opacity": "1", "display": "inline", "visibility": "visible", "color": "black",
+	"font-size": "medium", "font-style": "normal", "font-weight": "normal",
+	"text-anchor": "start", "clip-rule": "nonzero", "overflow": "visible",
+	"marker-start": "none", "marker-mid": "none", "marker-end": "none",
+	"stop-color": "black", "stop-opacity": "1", "flood-color": "black", "flood-opacity": "1",
+	"clip-path": "none", "mask": "none", "filter": "none", "pointer-events": "visiblePainted",
+}
+
+// inheritedProperties lists the properties which are inherited by default.
+var inheritedProperties = map[string]bool{
+	"fill": true, "fill-opacity": true, "fill-rule": true, "stroke": true,
+	"stroke-width": true, "stroke-opacity": true, "stroke-linecap": true,
+	"stroke-linejoin": true, "stroke-miterlimit": true, "stroke-dasharray": true,
+	"stroke-dashoffset": true, "visibility": true, "color": true, "font": true,
+	"font-family": true, "font-size": true, "font-style": true, "font-weight": true,
+	"font-variant": true, "font-stretch": true, "letter-spacing": true,
+	"word-spacing": true, "text-anchor": true, "direction": true, "writing-mode": true,
+	"dominant-baseline": true, "clip-rule": true, "marker-start": true,
+	"marker-mid": true, "marker-end": true, "color-interpolation": true,
+	"color-interpolation-filters": true, "shape-rendering": true,
+	"text-rendering": true, "image-rendering": true, "paint-order": true,
+	"pointer-events": true, "cursor": true,
+}
+
+// presentationAttributes lists the attributes of properties which are not
+// inherited and set the property of the same name.
+var presentationAttributes = map[string]bool{
+	"alignment-baseline": true, "baseline-shift": true, "clip": true, "clip-path": true,
+	"color-rendering": true, "display": true, "enable-background": true,
+	"flood-color": true, "flood-opacity": true, "glyph-orientation-horizontal": true,
+	"glyph-orientation-vertical": true, "kerning": true, "lighting-color": true,
+	"mask": true, "opacity": true, "overflow": true, "filter": true, "stop-color": true,
+	"stop-opacity": true, "text-decoration": true, "unicode-bidi": true,
+}
+
+// colorProperties lists the properties which accept currentColor.
+var colorProperties = map[string]bool{
+	"fill": true, "stroke": true, "stop-color": true, "flood-color": true, "lighting-color": true,
+}
+
+// isPresentationAttribute returns true if the attribute sets the property of
+// the same name, which is the case for all inherited properties.
+func isPresentationAttribute(name string) bool {
+	return presentationAttributes[name] || inheritedProperties[name] && name != "font" && name != "cursor"
+}
+
+// StyleOptions configures the cascade. Medium selects the @media rules which
+// apply and defaults to screen. Loader loads the stylesheets of @import
+// rules, which are refused if it is nil.
+type StyleOptions struct {
+	Medium string
+	Loader utils.ImportLoader
+}
+
+// Stylesheet returns the rules of all <style> elements of the document in
+// document order. The media attribute of a <style> element applies to its
+// rules, and elements with a type other than text/css are ignored.
+func (e *Element) Stylesheet(loader utils.ImportLoader) (*utils.Stylesheet, error) {
+	root := e.root()
+	styles := root.FindAll("style")
+	if root.Name == "style" {
+		styles = append([]*Element{root}, styles...)
+	}
+	sheet := &utils.Stylesheet{}
+	for _, style := range styles {
+		if t := strings.TrimSpace(style.Attributes["type"]); t != "" && t != "text/css" {
+			continue
+		}
+		s, err := utils.StylesheetParser(style.Content, loader)
+		if err != nil {
+			return nil, err
+		}
+		media := strings.TrimSpace(style.Attributes["media"])
+		for _, rule := range s.Rules {
+			if media != "" {
+				rule.Media = append(rule.Media[:len(rule.Media):len(rule.Media)], media)
+			}
+			sheet.Rules = append(sheet.Rules, rule)
+		}
+	}
+	return sheet, nil
+}
+
+// declaration is a declared value competing in the cascade. Origins are 0
+// for presentation attributes, 1 for stylesheets and 2 for the style
+// attribute.
+type declaration struct {
+	value       string
+	important   bool
+	origin      int
+	specificity [3]int
+	order       int
+}
+
+// precedes returns true if the declaration wins over o in the cascade.
+func (d declaration) precedes(o declaration) bool {
+	if d.important != o.important {
+		return d.important
+	}
+	if d.origin != o.origin {
+		return d.origin > o.origin
+	}
+	if c := utils.CompareSpecificity(d.specificity, o.specificity); c != 0 {
+		return c > 0
+	}
+	return d.order > o.order
+}
+
+// cascaded returns the winning declared value of every property declared for
+// the element.
+func (e *Element) cascaded(sheet *utils.Stylesheet, medium string) map[string]string {
+	winners := map[string]declaration{}
+	order := 0
+	declare := func(property string, d declaration) {
+		order++
+		d.order = order
+		if w, ok := winners[property]; !ok || d.precedes(w) {
+			winners[property] = d
+		}
+	}
+
+	for k, v := range e.Attributes {
+		if isPresentationAttribute(k) {
+			declare(k, declaration{value: strings.TrimSpace(v)})
+		}
+	}
+	for _, rule := range sheet.Rules {
+		applies := true
+		for _, m := range rule.Media {
+			applies = applies && utils.MediaMatches(m, medium)
+		}
+		if !applies {
+			continue
+		}
+		matched, specificity := false, [3]int{}
+		for _, s := range rule.Selectors {
+			if e.Matches(s) {
+				if sp := s.Specificity(); !matched || utils.CompareSpecificity(sp, specificity) > 0 {
+					specificity = sp
+				}
+				matched = true
+			}
+		}
+		if !matched {
+			continue
+		}
+		for _, d := range rule.Declarations {
+			declare(d.Property, declaration{value: d.Value, important: d.Important, origin: 1, specificity: specificity})
+		}
+	}
+	for _, d := range utils.StyleParser(e.Attributes["style"]) {
+		declare(d.Property, declaration{value: d.Value, important: d.Important, origin: 2})
+	}
+
+	values := make(map[string]string, len(winners))
+	for p, d := range winners {
+		values[p] = d.value
+	}
+	return values
+}
+
+// styleResolver computes the styles of the elements of a document with the
+// rules of its stylesheets. The computed values and font sizes of elements
+// are cached, so it must not outlive changes to the styles of the document.
+type styleResolver struct {
+	sheet     *utils.Stylesheet
+	medium    string
+	computed  map[*Element]map[string]string
+	fontSizes map[fontSizeKey]float64
+}
+
+// fontSizeKey identifies the font size of an element at a DPI.
+type fontSizeKey struct {
+	e   *Element
+	dpi float64
+}
+
+// styles returns a resolver for the styles of the document of the element.
+func (e *Element) styles(options StyleOptions) (*styleResolver, error) {
+	if options.Medium == "" {
+		options.Medium = "screen"
+	}
+	sheet, err := e.Stylesheet(options.Loader)
+	if err != nil {
+		return nil, err
+	}
+	return &styleResolver{sheet, options.Medium, map[*Element]map[string]string{}, map[fontSizeKey]float64{}}, nil
+}
+
+// defaultStyles returns a resolver with the default options, which cannot
+// fail because @import rules are refused without a loader.
+func (e *Element) defaultStyles() *styleResolver {
+	r, _ := e.styles(StyleOptions{})
+	return r
+}
+
+// declared returns the cascaded value of a property declared for the
+// element itself, or false if it is not declared.
+func (r *styleResolver) declared(e *Element, name string) (string, bool) {
+	v, ok := e.cascaded(r.sheet, r.medium)[name]
+	return v, ok
+}
+
+// values returns the computed values of the element, where currentColor is
+// kept to be resolved against the color of the element using it.
+func (r *styleResolver) values(e *Element) map[string]string {
+	if values, ok := r.computed[e]; ok {
+		return values
+	}
+	parent := initialValues
+	if e.Parent != nil {
+		parent = r.values(e.Parent)
+	}
+	declared := e.cascaded(r.sheet, r.medium)
+
+	values := map[string]string{}
+	for p, v := range initialValues {
+		values[p] = v
+	}
+	for p, v := range parent {
+		if inheritedProperties[p] || strings.HasPrefix(p, "--") {
+			values[p] = v
+		}
+	}
+	for p, v := range declared {
+		keyword := strings.ToLower(v)
+		if keyword == "unset" {
+			keyword = "initial"
+			if inheritedProperties[p] || strings.HasPrefix(p, "--") {
+				keyword = "inherit"
+			}
+		}
+		switch keyword {
+		case "inherit":
+			if pv, ok := parent[p]; ok {
+				values[p] = pv
+			} else {
+				delete(values, p)
+			}
+		case "initial":
+			if iv, ok := initialValues[p]; ok {
+				values[p] = iv
+			} else {
+				delete(values, p)
+			}
+		default:
+			values[p] = v
+		}
+	}
+	r.computed[e] = values
+	return values
+}
+
+// property returns the computed value of a property of the element with
+// currentColor resolved, or an empty string if it has none.
+func (r *styleResolver) property(e *Element, name string) string {
+	values := r.values(e)
+	v := values[name]
+	if colorProperties[name] && strings.EqualFold(v, "currentColor") {
+		return values["color"]
+	}
+	return v
+}
+
+// ComputedStyle returns the computed values of the properties of the
+// element after the cascade with the default options.
+func ComputedStyle(e *Element) (map[string]string, error) {
+	return ComputedStyleWithOptions(e, StyleOptions{})
+}
+
+// ComputedStyleWithOptions returns the computed values of the properties of
+// the element. The cascade combines the initial values of the user agent,
+// presentation attributes, the rules of the document's <style> elements by
+// specificity and order, and the style attribute, where !important
+// declarations take precedence. Inherited properties and those set to
+// inherit take the value of the parent, and currentColor is resolved to the
+// value of color. Values are not otherwise converted.
+func ComputedStyleWithOptions(e *Element, options StyleOptions) (map[string]string, error) {
+	r, err := e.styles(options)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for p := range r.values(e) {
+		values[p] = r.property(e, p)
+	}
+	return values, nil
+}
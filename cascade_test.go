@@ -0,0 +1,86 @@
+package svgparser_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	svgparser "github.com/chikamim/svgparser"
+)
+
+func TestComputedStyle(t *testing.T) {
+	svg := `
+		<svg>
+			<style><![CDATA[
+				@import "theme.css";
+				rect { fill: red; stroke: green !important }
+				.icon rect { fill: blue }
+				#special { fill: yellow }
+				@media print { .icon rect { fill: gray } }
+			]]></style>
+			<g class="icon" fill="purple" stroke-width="3" opacity="0.5" style="color: teal">
+				<rect id="plain" fill="orange" />
+				<rect id="special" style="fill: white; stroke: black" />
+				<rect id="important" style="stroke: black !important" />
+				<circle id="inherited" stroke="currentColor" />
+				<circle id="keywords" fill="inherit" stroke-width="initial" opacity="inherit" style="color: unset" />
+			</g>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	testCases := []struct {
+		id       string
+		property string
+		expected string
+	}{
+		{"plain", "fill", "blue"},
+		{"plain", "stroke", "green"},
+		{"plain", "stroke-width", "3"},
+		{"plain", "opacity", "1"},
+		{"special", "fill", "white"},
+		{"special", "stroke", "green"},
+		{"important", "stroke", "black"},
+		{"inherited", "fill", "purple"},
+		{"inherited", "stroke", "teal"},
+		{"inherited", "display", "inline"},
+		{"keywords", "fill", "purple"},
+		{"keywords", "stroke-width", "1"},
+		{"keywords", "opacity", "0.5"},
+		{"keywords", "color", "teal"},
+	}
+
+	for _, test := range testCases {
+		style, err := svgparser.ComputedStyle(element.FindID(test.id))
+		if err != nil {
+			t.Errorf("ComputedStyle %s failed: %v\n", test.id, err)
+		} else if actual := style[test.property]; actual != test.expected {
+			t.Errorf("ComputedStyle %s %s: expected %q, actual %q\n", test.id, test.property, test.expected, actual)
+		}
+	}
+
+	options := svgparser.StyleOptions{
+		Medium: "print",
+		Loader: func(url string) (string, error) { return "circle { fill: navy }", nil },
+	}
+	style, _ := svgparser.ComputedStyleWithOptions(element.FindID("plain"), options)
+	if style["fill"] != "gray" {
+		t.Errorf("ComputedStyle print: expected %q, actual %q\n", "gray", style["fill"])
+	}
+	style, _ = svgparser.ComputedStyleWithOptions(element.FindID("inherited"), options)
+	if style["fill"] != "navy" {
+		t.Errorf("ComputedStyle import: expected %q, actual %q\n", "navy", style["fill"])
+	}
+}
+
+func TestComposeStyle(t *testing.T) {
+	svg := `<svg><style>rect > g { fill: red }</style></svg>`
+	element, _ := parse(svg, false)
+	var buf bytes.Buffer
+	if err := element.Compose(&buf); err != nil {
+		t.Fatalf("Compose failed: %v\n", err)
+	}
+	actual, _ := svgparser.Parse(strings.NewReader(buf.String()), false)
+	if !element.Compare(actual) {
+		t.Errorf("Compose: expected style content %q, actual %q\n", element.Children[0].Content, actual.Children[0].Content)
+	}
+}
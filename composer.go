@@ -44,6 +44,12 @@ func EncodeXML(r *Element, e *xml.Encoder, excludes []string) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to encode start element: %v", err)
 	}
+	if r.Content != "" {
+		err = e.EncodeToken(xml.CharData(r.Content))
+		if err != nil {
+			return fmt.Errorf("failed to encode content: %v", err)
+		}
+	}
 	for _, c := range r.Children {
 		EncodeXML(c, e, excludes)
 	}
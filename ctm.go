@@ -0,0 +1,137 @@
+package svgparser
+
+import (
+	"github.com/chikamim/svgparser/utils"
+)
+
+// Transform returns the matrix of the element's transform attribute.
+func (e *Element) Transform() (utils.Matrix, error) {
+	return utils.TransformParser(e.Attributes["transform"])
+}
+
+// viewportTransform returns the transform which an <svg> element establishes
+// for its children: the x/y offset of nested viewports and the viewBox
+// mapping.
+func (e *Element) viewportTransform(r *styleResolver) utils.Matrix {
+	if vb, ok := e.viewBox(); ok {
+		if p, err := e.PreserveAspectRatio(); err == nil {
+			x, y, w, h := e.viewport(r, vb)
+			return p.Transform(vb, x, y, w, h)
+		}
+	}
+	if e.Parent == nil {
+		return utils.Identity()
+	}
+	return utils.Translate(e.viewportLength(r, "x", 0), e.viewportLength(r, "y", 0))
+}
+
+// symbolTransform returns the transform which a <symbol> establishes for its
+// children when instanced by use, whose width and height override those of
+// the symbol.
+func symbolTransform(r *styleResolver, symbol, use *Element) utils.Matrix {
+	vb, ok := symbol.viewBox()
+	if !ok {
+		return utils.Identity()
+	}
+	p, err := symbol.PreserveAspectRatio()
+	if err != nil {
+		p = utils.DefaultPreserveAspectRatio
+	}
+	_, _, w, h := symbol.viewport(r, vb)
+	w, h = use.attributeLength(r, "width", w), use.attributeLength(r, "height", h)
+	return p.Transform(vb, 0, 0, w, h)
+}
+
+// localTransform returns the transform the element contributes to the
+// coordinate system of its children.
+func (e *Element) localTransform(r *styleResolver) (utils.Matrix, error) {
+	m, err := e.Transform()
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	if e.Name == "svg" {
+		m = m.Multiply(e.viewportTransform(r))
+	}
+	return m, nil
+}
+
+// chainTransform returns the product of the element's own transform and the
+// local transforms of its ancestors up to, but excluding, stop.
+func (e *Element) chainTransform(r *styleResolver, stop *Element) (utils.Matrix, error) {
+	m, err := e.Transform()
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	for p := e.Parent; p != nil && p != stop; p = p.Parent {
+		local, err := p.localTransform(r)
+		if err != nil {
+			return utils.Matrix{}, err
+		}
+		m = local.Multiply(m)
+	}
+	return m, nil
+}
+
+// CTM returns the current transformation matrix of the element, which maps
+// the element's user space to the viewport of the root element. It combines
+// the transform attributes of the element and its ancestors with the
+// viewports established by <svg> ancestors.
+func (e *Element) CTM() (utils.Matrix, error) {
+	return e.chainTransform(e.defaultStyles(), nil)
+}
+
+// UseCTM returns the current transformation matrix of the element as it is
+// rendered through the given <use> element, which references the element or
+// one of its ancestors.
+func (e *Element) UseCTM(use *Element) (utils.Matrix, error) {
+	id := use.hrefID()
+	target := use.root().FindID(id)
+	if target == nil {
+		return utils.Matrix{}, ValidationError{"use references missing element #" + id}
+	}
+
+	found := target == e
+	for _, a := range e.Ancestors() {
+		found = found || a == target
+	}
+	if !found {
+		return utils.Matrix{}, ValidationError{"element is not referenced by use #" + id}
+	}
+
+	r := e.defaultStyles()
+	m, err := e.chainTransform(r, target)
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	if target != e {
+		if target.Name == "symbol" {
+			m = symbolTransform(r, target, use).Multiply(m)
+		} else {
+			t, err := target.Transform()
+			if err != nil {
+				return utils.Matrix{}, err
+			}
+			m = t.Multiply(m)
+		}
+	}
+
+	ctm, err := use.chainTransform(r, nil)
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	offset, err := use.useOffset(r)
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	return ctm.Multiply(offset).Multiply(m), nil
+}
+
+// useOffset returns the translation by the x and y of a <use> element, or an
+// error if they are invalid.
+func (e *Element) useOffset(r *styleResolver) (utils.Matrix, error) {
+	n, err := e.geometryNumbers(r, true, "x", "y")
+	if err != nil {
+		return utils.Matrix{}, err
+	}
+	return utils.Translate(n[0], n[1]), nil
+}
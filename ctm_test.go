@@ -0,0 +1,62 @@
+package svgparser_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func closePoints(a, b utils.Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestCTM(t *testing.T) {
+	svg := `
+		<svg width="200" height="100" viewBox="0 0 100 50">
+			<g transform="translate(10 10)">
+				<svg x="5" y="5" width="20" height="20" viewBox="0 0 10 10">
+					<rect id="box" x="1" y="1" width="2" height="2" transform="scale(2)"/>
+				</svg>
+			</g>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	ctm, err := element.FindID("box").CTM()
+	if err != nil {
+		t.Fatalf("CTM failed: %v\n", err)
+	}
+
+	// (1,1) -> scale(2) (2,2) -> viewBox (4,4) -> x/y (9,9) -> translate (19,19) -> root viewBox (38,38)
+	expected := utils.Point{X: 38, Y: 38}
+	if p := ctm.Apply(utils.Point{X: 1, Y: 1}); !closePoints(p, expected) {
+		t.Errorf("CTM: expected %v, actual %v\n", expected, p)
+	}
+}
+
+func TestUseCTM(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<defs>
+				<symbol id="icon" viewBox="0 0 10 10">
+					<g transform="translate(1 1)"><circle id="dot" r="1"/></g>
+				</symbol>
+			</defs>
+			<use id="instance" xlink:href="#icon" x="50" y="50" width="20" height="20"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	ctm, err := element.FindID("dot").UseCTM(element.FindID("instance"))
+	if err != nil {
+		t.Fatalf("UseCTM failed: %v\n", err)
+	}
+
+	expected := utils.Point{X: 52, Y: 52}
+	if p := ctm.Apply(utils.Point{X: 0, Y: 0}); !closePoints(p, expected) {
+		t.Errorf("UseCTM: expected %v, actual %v\n", expected, p)
+	}
+
+	if _, err := element.FindID("instance").UseCTM(element.FindID("instance")); err == nil {
+		t.Error("UseCTM: expected error for unrelated element")
+	}
+}
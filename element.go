@@ -112,3 +112,23 @@ func (e *Element) Hash() string {
 	b64, _ := basex.NewEncoding("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 	return b64.Encode(hasher.Sum(nil))
 }
+
+// Clone returns a deep copy of the element and its descendants. The copy has
+// no parent.
+func (e *Element) Clone() *Element {
+	c := &Element{
+		UUID:       e.UUID,
+		Name:       e.Name,
+		Attributes: make(map[string]string, len(e.Attributes)),
+		Content:    e.Content,
+	}
+	for k, v := range e.Attributes {
+		c.Attributes[k] = v
+	}
+	for _, child := range e.Children {
+		cc := child.Clone()
+		cc.Parent = c
+		c.Children = append(c.Children, cc)
+	}
+	return c
+}
@@ -2,6 +2,7 @@ package svgparser
 
 import (
 	"regexp"
+	"strings"
 )
 
 // FindID finds the first child with the specified ID.
@@ -144,3 +145,54 @@ func (e *Element) FindLinkedIDs() []string {
 	}
 	return ids
 }
+
+var urlReference = regexp.MustCompile(`url\(\s*['"]?#([^'")\s]+)['"]?\s*\)`)
+
+// hrefID returns the id referenced by the href or xlink:href attribute.
+func (e *Element) hrefID() string {
+	for _, key := range []string{"href", "xlink:href"} {
+		if v, ok := e.Attributes[key]; ok && strings.HasPrefix(strings.TrimSpace(v), "#") {
+			return strings.TrimSpace(v)[1:]
+		}
+	}
+	return ""
+}
+
+// referencedIDs returns the ids referenced by the element's attributes, either
+// as href fragment or as url() function.
+func (e *Element) referencedIDs() []string {
+	ids := []string{}
+	if id := e.hrefID(); id != "" {
+		ids = append(ids, id)
+	}
+	for _, v := range e.Attributes {
+		for _, m := range urlReference.FindAllStringSubmatch(v, -1) {
+			ids = append(ids, m[1])
+		}
+	}
+	return ids
+}
+
+// renameReferences rewrites all references to the renamed ids in the element
+// and its descendants.
+func renameReferences(e *Element, renamed map[string]string) {
+	for k, v := range e.Attributes {
+		if k == "href" || k == "xlink:href" {
+			id := strings.TrimPrefix(strings.TrimSpace(v), "#")
+			if n, ok := renamed[id]; ok && id != v {
+				e.Attributes[k] = "#" + n
+			}
+			continue
+		}
+		e.Attributes[k] = urlReference.ReplaceAllStringFunc(v, func(s string) string {
+			id := urlReference.FindStringSubmatch(s)[1]
+			if n, ok := renamed[id]; ok {
+				return "url(#" + n + ")"
+			}
+			return s
+		})
+	}
+	for _, child := range e.Children {
+		renameReferences(child, renamed)
+	}
+}
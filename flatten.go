@@ -0,0 +1,376 @@
+package svgparser
+
+import (
+	"math"
+	"strings"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// elements whose content is not rendered in the coordinate system of their
+// parent and which are therefore left untouched when flattening transforms.
+var nonRenderedElements = map[string]bool{
+	"defs": true, "symbol": true, "clipPath": true, "mask": true,
+	"pattern": true, "marker": true, "linearGradient": true,
+	"radialGradient": true, "filter": true, "style": true, "script": true,
+	"title": true, "desc": true, "metadata": true,
+}
+
+// similarityScale returns the uniform scale factor of a matrix which only
+// rotates, reflects, translates and scales uniformly.
+func similarityScale(m utils.Matrix) (float64, bool) {
+	s := math.Sqrt(math.Abs(m.Determinant()))
+	tolerance := 1e-9 * math.Max(1, s*s)
+	if math.Abs(m.A*m.A+m.B*m.B-s*s) > tolerance || math.Abs(m.C*m.C+m.D*m.D-s*s) > tolerance ||
+		math.Abs(m.A*m.C+m.B*m.D) > tolerance {
+		return 0, false
+	}
+	return s, true
+}
+
+func isAxisAligned(m utils.Matrix) bool {
+	return math.Abs(m.B) < 1e-12 && math.Abs(m.C) < 1e-12
+}
+
+// gradientUnits returns the gradientUnits of a gradient, which are inherited
+// from the gradients it references if it does not specify them.
+func gradientUnits(gradient *Element) string {
+	visited := map[*Element]bool{}
+	for gradient != nil && !visited[gradient] {
+		if units, ok := gradient.Attributes["gradientUnits"]; ok {
+			return strings.TrimSpace(units)
+		}
+		visited[gradient] = true
+		id := gradient.hrefID()
+		if id == "" {
+			break
+		}
+		gradient = gradient.root().FindID(id)
+	}
+	return "objectBoundingBox"
+}
+
+// usesResources returns true if the element renders resources in its user
+// space, which would not follow the matrix if it were applied to the
+// geometry: clipping paths, masks and filters of groups and shapes, markers
+// of shapes and the paint servers of their fill and stroke. Gradients in
+// objectBoundingBox units follow the geometry under positive axis aligned
+// matrices.
+func (r *styleResolver) usesResources(e *Element, m utils.Matrix) bool {
+	for _, name := range []string{"clip-path", "mask", "filter"} {
+		if v := r.property(e, name); v != "" && v != "none" {
+			return true
+		}
+	}
+	if _, ok := shapeAttributes[e.Name]; !ok && e.Name != "path" {
+		return false
+	}
+	for _, name := range []string{"marker-start", "marker-mid", "marker-end"} {
+		if v := r.property(e, name); v != "" && v != "none" {
+			return true
+		}
+	}
+	for _, name := range []string{"fill", "stroke"} {
+		match := urlReference.FindStringSubmatch(r.property(e, name))
+		if match == nil {
+			continue
+		}
+		server := e.root().FindID(match[1])
+		if server == nil {
+			continue
+		}
+		switch server.Name {
+		case "pattern":
+			return true
+		case "linearGradient", "radialGradient":
+			if gradientUnits(server) == "userSpaceOnUse" || !isAxisAligned(m) || m.A <= 0 || m.D <= 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scaleStrokeWidth scales the stroke width of the element by the uniform
+// scale of the matrix if it paints a stroke. Non-uniform scaling cannot be
+// represented and leaves the stroke width unchanged. A stroke width in the
+// style attribute is changed there, keeping its priority.
+func scaleStrokeWidth(r *styleResolver, e *Element, m utils.Matrix) {
+	s, ok := similarityScale(m)
+	if !ok || math.Abs(s-1) < 1e-12 {
+		return
+	}
+	if _, painted := r.paintedStroke(e); !painted {
+		return
+	}
+	l, err := utils.LengthParser(r.property(e, "stroke-width"))
+	if err != nil {
+		return
+	}
+	scaled := formatNumber(l.Resolve(e.lengthContext(r, 0), utils.Diagonal) * s)
+
+	styles := utils.StyleParser(e.Attributes["style"])
+	found := false
+	for _, st := range styles {
+		if st.Property == "stroke-width" {
+			st.Value, found = scaled, true
+		}
+	}
+	if found {
+		e.Attributes["style"] = styles.String()
+		return
+	}
+	e.Attributes["stroke-width"] = scaled
+}
+
+// radii returns the rx and ry of a rect or ellipse in user units, where a
+// missing or auto radius takes the value of the other, or false if both are
+// missing.
+func (e *Element) radii(r *styleResolver) (float64, float64, bool, error) {
+	rx, err := e.geometryNumber(r, "rx", -1, false)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	ry, err := e.geometryNumber(r, "ry", -1, false)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if rx < 0 && ry < 0 {
+		return 0, 0, false, nil
+	}
+	if rx < 0 {
+		rx = ry
+	}
+	if ry < 0 {
+		ry = rx
+	}
+	return rx, ry, true, nil
+}
+
+// FlattenTransforms bakes the transform attributes of the element and its
+// descendants into their coordinates. Group transforms are pushed down to the
+// leaves, path data is transformed, and rects, circles and ellipses are
+// converted to paths when the transform cannot be represented by their
+// attributes. Lengths with units and percentages are resolved into user
+// units, and automatic radii are written out. The widths of painted strokes
+// are scaled for uniform transforms. Elements whose geometry cannot be
+// transformed, such as text and images, keep the accumulated transform, as
+// do groups and shapes which render clipping paths, masks, filters, markers
+// or paint servers in their user space. If an element has invalid
+// geometry its error is returned and the document is left unchanged.
+func (e *Element) FlattenTransforms() error {
+	// shapes may be renamed to paths, so names are restored with attributes
+	type state struct {
+		name       string
+		attributes map[string]string
+	}
+	saved := map[*Element]state{}
+	for _, el := range append([]*Element{e}, e.Descendants()...) {
+		attributes := make(map[string]string, len(el.Attributes))
+		for k, v := range el.Attributes {
+			attributes[k] = v
+		}
+		saved[el] = state{el.Name, attributes}
+	}
+	if err := flattenTransforms(e.defaultStyles(), e, utils.Identity()); err != nil {
+		for el, s := range saved {
+			el.Name, el.Attributes = s.name, s.attributes
+		}
+		return err
+	}
+	return nil
+}
+
+func flattenTransforms(r *styleResolver, e *Element, m utils.Matrix) error {
+	if nonRenderedElements[e.Name] {
+		return nil
+	}
+	t, err := e.Transform()
+	if err != nil {
+		return err
+	}
+	m = m.Multiply(t)
+	delete(e.Attributes, "transform")
+	if !m.IsIdentity() && r.usesResources(e, m) {
+		e.Attributes["transform"] = m.String()
+		m = utils.Identity()
+	}
+
+	switch e.Name {
+	case "g", "a", "switch":
+		for _, child := range e.Children {
+			if err := flattenTransforms(r, child, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "svg":
+		inner := m
+		if e.Parent != nil {
+			if !(m.IsIdentity() || isAxisAligned(m) && m.A > 0 && m.D > 0) {
+				e.Attributes["transform"] = m.String()
+				return nil
+			}
+			if err := flattenViewport(r, e, m); err != nil {
+				return err
+			}
+			inner = utils.Identity()
+		}
+		for _, child := range e.Children {
+			if err := flattenTransforms(r, child, inner); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if m.IsIdentity() {
+		return nil
+	}
+	return flattenShape(r, e, m)
+}
+
+// flattenViewport applies the positive axis aligned matrix to the position
+// and size of a nested <svg> element.
+func flattenViewport(r *styleResolver, e *Element, m utils.Matrix) error {
+	n, err := e.geometryNumbers(r, true, "x", "y")
+	if err != nil {
+		return err
+	}
+	w, err := e.geometryNumber(r, "width", math.NaN(), false)
+	if err != nil {
+		return err
+	}
+	h, err := e.geometryNumber(r, "height", math.NaN(), false)
+	if err != nil {
+		return err
+	}
+	p := m.Apply(utils.Point{X: n[0], Y: n[1]})
+	e.Attributes["x"], e.Attributes["y"] = formatNumber(p.X), formatNumber(p.Y)
+	if !math.IsNaN(w) {
+		e.Attributes["width"] = formatNumber(w * m.A)
+	}
+	if !math.IsNaN(h) {
+		e.Attributes["height"] = formatNumber(h * m.D)
+	}
+	return nil
+}
+
+// flattenShape applies the matrix to the geometry of a leaf element, or sets
+// it as transform of elements whose geometry cannot be transformed. Nothing
+// is changed if an error is returned.
+func flattenShape(r *styleResolver, e *Element, m utils.Matrix) error {
+	switch e.Name {
+	case "path":
+		path, err := utils.PathParser(e.Attributes["d"])
+		if err != nil {
+			return err
+		}
+		e.Attributes["d"] = path.Transform(m).String()
+	case "rect":
+		if !isAxisAligned(m) {
+			return flattenShapeAsPath(r, e, m)
+		}
+		n, err := e.geometryNumbers(r, true, "x", "y")
+		if err != nil {
+			return err
+		}
+		size, err := e.geometryNumbers(r, false, "width", "height")
+		if err != nil {
+			return err
+		}
+		rx, ry, rounded, err := e.radii(r)
+		if err != nil {
+			return err
+		}
+		p := m.Apply(utils.Point{X: n[0], Y: n[1]})
+		w, h := size[0]*m.A, size[1]*m.D
+		if w < 0 {
+			p.X, w = p.X+w, -w
+		}
+		if h < 0 {
+			p.Y, h = p.Y+h, -h
+		}
+		e.Attributes["x"], e.Attributes["y"] = formatNumber(p.X), formatNumber(p.Y)
+		e.Attributes["width"], e.Attributes["height"] = formatNumber(w), formatNumber(h)
+		if rounded {
+			e.Attributes["rx"], e.Attributes["ry"] = formatNumber(rx*math.Abs(m.A)), formatNumber(ry*math.Abs(m.D))
+		}
+	case "circle":
+		s, ok := similarityScale(m)
+		if !ok {
+			return flattenShapeAsPath(r, e, m)
+		}
+		n, err := e.geometryNumbers(r, true, "cx", "cy")
+		if err != nil {
+			return err
+		}
+		radius, err := e.geometryNumber(r, "r", 0, false)
+		if err != nil {
+			return err
+		}
+		c := m.Apply(utils.Point{X: n[0], Y: n[1]})
+		e.Attributes["cx"], e.Attributes["cy"] = formatNumber(c.X), formatNumber(c.Y)
+		e.Attributes["r"] = formatNumber(radius * s)
+	case "ellipse":
+		if !isAxisAligned(m) {
+			return flattenShapeAsPath(r, e, m)
+		}
+		n, err := e.geometryNumbers(r, true, "cx", "cy")
+		if err != nil {
+			return err
+		}
+		rx, ry, sized, err := e.radii(r)
+		if err != nil {
+			return err
+		}
+		c := m.Apply(utils.Point{X: n[0], Y: n[1]})
+		e.Attributes["cx"], e.Attributes["cy"] = formatNumber(c.X), formatNumber(c.Y)
+		if sized {
+			e.Attributes["rx"], e.Attributes["ry"] = formatNumber(rx*math.Abs(m.A)), formatNumber(ry*math.Abs(m.D))
+		}
+	case "line":
+		n, err := e.geometryNumbers(r, true, "x1", "y1", "x2", "y2")
+		if err != nil {
+			return err
+		}
+		p1, p2 := m.Apply(utils.Point{X: n[0], Y: n[1]}), m.Apply(utils.Point{X: n[2], Y: n[3]})
+		e.Attributes["x1"], e.Attributes["y1"] = formatNumber(p1.X), formatNumber(p1.Y)
+		e.Attributes["x2"], e.Attributes["y2"] = formatNumber(p2.X), formatNumber(p2.Y)
+	case "polyline", "polygon":
+		numbers, err := utils.NumberListParser(e.Attributes["points"])
+		if err != nil {
+			return err
+		}
+		points := make([]string, 0, len(numbers)/2)
+		for i := 0; i+1 < len(numbers); i += 2 {
+			p := m.Apply(utils.Point{X: numbers[i], Y: numbers[i+1]})
+			points = append(points, formatNumber(p.X)+","+formatNumber(p.Y))
+		}
+		e.Attributes["points"] = strings.Join(points, " ")
+	default:
+		e.Attributes["transform"] = m.String()
+		return nil
+	}
+	scaleStrokeWidth(r, e, m)
+	return nil
+}
+
+// flattenShapeAsPath converts a rect, circle or ellipse into a path with the
+// matrix applied.
+func flattenShapeAsPath(r *styleResolver, e *Element, m utils.Matrix) error {
+	path, err := e.path(r)
+	if err != nil {
+		return err
+	}
+	if len(path.Subpaths) == 0 {
+		return nil
+	}
+	for _, k := range shapeAttributes[e.Name] {
+		delete(e.Attributes, k)
+	}
+	e.Name = "path"
+	e.Attributes["d"] = path.Transform(m).String()
+	scaleStrokeWidth(r, e, m)
+	return nil
+}
@@ -0,0 +1,166 @@
+package svgparser_test
+
+import (
+	"testing"
+)
+
+func TestFlattenTransforms(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<g transform="translate(10 20)" stroke="black" stroke-width="2">
+				<path id="path" d="M0 0 h10 v10 z" transform="scale(2)"/>
+				<rect id="rect" x="1" y="2" width="3" height="4" transform="scale(2 3)"/>
+				<circle id="circle" cx="5" cy="5" r="2"/>
+				<ellipse id="ellipse" cx="0" cy="0" rx="2" ry="1" transform="rotate(90)"/>
+				<polygon id="polygon" points="0,0 1,0 1,1"/>
+				<text id="text" transform="rotate(45)">label</text>
+			</g>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.FlattenTransforms(); err != nil {
+		t.Fatalf("FlattenTransforms failed: %v\n", err)
+	}
+
+	var testCases = []struct {
+		id, attribute, expected string
+	}{
+		{"path", "d", "M 10 20 L 30 20 L 30 40 Z"},
+		{"path", "stroke-width", "4"},
+		{"rect", "x", "12"},
+		{"rect", "y", "26"},
+		{"rect", "width", "6"},
+		{"rect", "height", "12"},
+		{"rect", "stroke-width", ""},
+		{"circle", "cx", "15"},
+		{"circle", "cy", "25"},
+		{"circle", "r", "2"},
+		{"polygon", "points", "10,20 11,20 11,21"},
+		{"text", "transform", "translate(10 20) rotate(45)"},
+	}
+	for _, test := range testCases {
+		if actual := element.FindID(test.id).Attributes[test.attribute]; actual != test.expected {
+			t.Errorf("FlattenTransforms %s %s: expected %q, actual %q\n", test.id, test.attribute, test.expected, actual)
+		}
+	}
+
+	if ellipse := element.FindID("ellipse"); ellipse.Name != "path" || ellipse.Attributes["rx"] != "" {
+		t.Errorf("FlattenTransforms: expected ellipse converted to path, actual %v\n", ellipse)
+	}
+	for _, e := range element.Descendants() {
+		if _, ok := e.Attributes["transform"]; ok && e.Name != "text" {
+			t.Errorf("FlattenTransforms: transform left on %v\n", e.Name)
+		}
+	}
+}
+
+func TestFlattenTransformsLengths(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<rect id="units" width="50%" height="10mm" transform="translate(10 0)"/>
+			<rect id="rounded" width="10" height="10" rx="2" transform="scale(2 1)"/>
+			<ellipse id="ellipse" ry="3" transform="scale(1 2)"/>
+			<path id="important" d="M0 0 H1" stroke="black" style="fill: red; stroke-width: 2 !important" transform="scale(2)"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.FlattenTransforms(); err != nil {
+		t.Fatalf("FlattenTransforms failed: %v\n", err)
+	}
+
+	var testCases = []struct {
+		id, attribute, expected string
+	}{
+		{"units", "x", "10"},
+		{"units", "width", "50"},
+		{"units", "height", "37.795275590551185"},
+		{"rounded", "rx", "4"},
+		{"rounded", "ry", "2"},
+		{"ellipse", "rx", "3"},
+		{"ellipse", "ry", "6"},
+		{"important", "style", "fill:red;stroke-width:4 !important"},
+	}
+	for _, test := range testCases {
+		if actual := element.FindID(test.id).Attributes[test.attribute]; actual != test.expected {
+			t.Errorf("FlattenTransforms %s %s: expected %q, actual %q\n", test.id, test.attribute, test.expected, actual)
+		}
+	}
+}
+
+func TestFlattenTransformsResources(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<defs>
+				<clipPath id="clip"><rect width="5" height="5"/></clipPath>
+				<linearGradient id="user" gradientUnits="userSpaceOnUse" x2="10"/>
+				<linearGradient id="inherited" href="#user"/>
+				<linearGradient id="bounding"/>
+			</defs>
+			<g id="clipped" transform="scale(2)" clip-path="url(#clip)">
+				<rect id="inner" width="10" height="10" transform="translate(1 0)"/>
+			</g>
+			<rect id="userspace" width="10" height="10" fill="url(#user)" transform="scale(2)"/>
+			<rect id="template" width="10" height="10" stroke="url(#inherited)" transform="scale(2)"/>
+			<rect id="translated" width="10" height="10" fill="url(#bounding)" transform="translate(5 0)"/>
+			<rect id="rotated" width="10" height="10" fill="url(#bounding)" transform="rotate(90)"/>
+			<rect id="unstroked" width="10" height="10" stroke-width="3" transform="scale(2)"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.FlattenTransforms(); err != nil {
+		t.Fatalf("FlattenTransforms failed: %v\n", err)
+	}
+
+	var testCases = []struct {
+		id, attribute, expected string
+	}{
+		{"clipped", "transform", "scale(2)"},
+		{"inner", "x", "1"},
+		{"inner", "transform", ""},
+		{"userspace", "transform", "scale(2)"},
+		{"userspace", "width", "10"},
+		{"template", "transform", "scale(2)"},
+		{"translated", "x", "5"},
+		{"translated", "transform", ""},
+		{"rotated", "transform", "rotate(90)"},
+		{"unstroked", "width", "20"},
+		{"unstroked", "stroke-width", "3"},
+	}
+	for _, test := range testCases {
+		if actual := element.FindID(test.id).Attributes[test.attribute]; actual != test.expected {
+			t.Errorf("FlattenTransforms %s %s: expected %q, actual %q\n", test.id, test.attribute, test.expected, actual)
+		}
+	}
+}
+
+func TestFlattenTransformsErrors(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<g id="group" transform="scale(2)">
+				<path id="path" d="M0 0 L x"/>
+				<rect id="rect" width="10" height="10"/>
+			</g>
+			<rect id="invalid" width="ten" height="10" transform="translate(10 0)"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.FlattenTransforms(); err == nil {
+		t.Error("FlattenTransforms: expected error for invalid geometry")
+	}
+
+	var testCases = []struct {
+		id, attribute, expected string
+	}{
+		{"invalid", "transform", "translate(10 0)"},
+		{"invalid", "width", "ten"},
+		{"group", "transform", "scale(2)"},
+		{"path", "d", "M0 0 L x"},
+		{"rect", "width", "10"},
+		{"rect", "transform", ""},
+	}
+	for _, test := range testCases {
+		if actual := element.FindID(test.id).Attributes[test.attribute]; actual != test.expected {
+			t.Errorf("FlattenTransforms %s %s: expected %q, actual %q\n", test.id, test.attribute, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package svgparser
+
+import (
+	"github.com/chikamim/svgparser/utils"
+)
+
+// stroke returns the stroke of the element for hit testing and bounding
+// boxes from its computed style, regardless of whether it is painted.
+func (r *styleResolver) stroke(e *Element) utils.Stroke {
+	s := utils.Stroke{Width: 1}
+	if l, err := utils.LengthParser(r.property(e, "stroke-width")); err == nil {
+		s.Width = l.Resolve(e.lengthContext(r, 0), utils.Diagonal)
+	}
+	s.LineJoin = r.property(e, "stroke-linejoin")
+	s.LineCap = r.property(e, "stroke-linecap")
+	if n, err := parseNumber(r.property(e, "stroke-miterlimit")); err == nil {
+		s.MiterLimit = n
+	}
+	return s
+}
+
+// paintedStroke returns the stroke of the element, or false if it has no
+// stroke or a stroke of zero width.
+func (r *styleResolver) paintedStroke(e *Element) (utils.Stroke, bool) {
+	if r.property(e, "stroke") == "none" {
+		return utils.Stroke{}, false
+	}
+	s := r.stroke(e)
+	return s, s.Width > 0
+}
+
+// pointerTargets returns whether the fill and the stroke of the element
+// receive pointer events, according to its pointer-events and visibility
+// properties and whether fill and stroke are painted.
+func (r *styleResolver) pointerTargets(e *Element) (bool, bool) {
+	visible := r.property(e, "visibility") == "visible"
+	fill := r.property(e, "fill") != "none" || e.Name == "image"
+	_, stroke := r.paintedStroke(e)
+	switch r.property(e, "pointer-events") {
+	case "none":
+		return false, false
+	case "visibleFill":
+		return visible, false
+	case "visibleStroke":
+		return false, visible
+	case "visible":
+		return visible, visible
+	case "painted":
+		return fill, stroke
+	case "fill":
+		return true, false
+	case "stroke":
+		return false, true
+	case "all":
+		return true, true
+	}
+	// visiblePainted
+	return visible && fill, visible && stroke
+}
+
+// hitShape returns true if the point in the user space of the shape hits its
+// fill or stroke, or an error if its geometry is invalid.
+func (r *styleResolver) hitShape(e *Element, p utils.Point) (bool, error) {
+	fill, stroke := r.pointerTargets(e)
+	if !fill && !stroke {
+		return false, nil
+	}
+	if e.Name == "image" {
+		box, err := e.imageBox(r)
+		if err != nil || box.IsEmpty() {
+			return false, err
+		}
+		return p.X >= box.Min.X && p.X <= box.Max.X && p.Y >= box.Min.Y && p.Y <= box.Max.Y, nil
+	}
+	if _, ok := shapeAttributes[e.Name]; !ok && e.Name != "path" {
+		return false, nil
+	}
+	path, err := e.path(r)
+	if err != nil {
+		return false, err
+	}
+	if len(path.Subpaths) == 0 {
+		return false, nil
+	}
+	// lines and polylines are filled like paths, which for lines is empty
+	if fill && path.Contains(p.X, p.Y, utils.ParseFillRule(r.property(e, "fill-rule"))) {
+		return true, nil
+	}
+	if s := r.stroke(e); stroke && s.Width > 0 {
+		return path.StrokeContains(p.X, p.Y, s), nil
+	}
+	return false, nil
+}
+
+// hitTest returns the topmost element at the point below e, where m maps the
+// user space of e's parent to the coordinate system of the point.
+func (r *styleResolver) hitTest(e *Element, m utils.Matrix, p utils.Point, visiting map[*Element]bool) (*Element, error) {
+	if nonRenderedElements[e.Name] {
+		return nil, nil
+	}
+	if r.property(e, "display") == "none" {
+		return nil, nil
+	}
+
+	switch e.Name {
+	case "svg", "g", "a", "switch":
+		local, err := e.localTransform(r)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(e.Children) - 1; i >= 0; i-- {
+			hit, err := r.hitTest(e.Children[i], m.Multiply(local), p, visiting)
+			if hit != nil || err != nil {
+				return hit, err
+			}
+		}
+		return nil, nil
+	case "use":
+		target := e.root().FindID(e.hrefID())
+		if target == nil || visiting[target] {
+			return nil, nil
+		}
+		t, err := e.Transform()
+		if err != nil {
+			return nil, err
+		}
+		offset, err := e.useOffset(r)
+		if err != nil {
+			return nil, err
+		}
+		m = m.Multiply(t).Multiply(offset)
+		visiting[target] = true
+		defer delete(visiting, target)
+
+		var hit *Element
+		if target.Name == "symbol" {
+			if t, err = target.Transform(); err != nil {
+				return nil, err
+			}
+			inner := m.Multiply(t).Multiply(symbolTransform(r, target, e))
+			for i := len(target.Children) - 1; i >= 0 && hit == nil && err == nil; i-- {
+				hit, err = r.hitTest(target.Children[i], inner, p, visiting)
+			}
+		} else {
+			hit, err = r.hitTest(target, m, p, visiting)
+		}
+		if hit != nil {
+			return e, err
+		}
+		return nil, err
+	}
+
+	t, err := e.Transform()
+	if err != nil {
+		return nil, err
+	}
+	inverse, err := m.Multiply(t).Invert()
+	if err != nil {
+		// a singular transform renders nothing
+		return nil, nil
+	}
+	hit, err := r.hitShape(e, inverse.Apply(p))
+	if err != nil || !hit {
+		return nil, err
+	}
+	return e, nil
+}
+
+// HitTest returns the topmost element rendered at the point (x, y), or nil if
+// there is none. The point is given in the coordinate system which CTM maps
+// to. Elements are tested in reverse paint order, applying their transforms,
+// and fill and stroke are tested with their fill rule, stroke width, line
+// joins and line caps. Styles are computed with the cascade, including the
+// document's <style> elements, and pointer-events and visibility select
+// whether fill and stroke can be hit. Elements hit through a <use> element
+// return the <use> element. Invalid geometry of a tested element gives an
+// error.
+func HitTest(root *Element, x, y float64) (*Element, error) {
+	r := root.defaultStyles()
+	m := utils.Identity()
+	for p := root.Parent; p != nil; p = p.Parent {
+		local, err := p.localTransform(r)
+		if err != nil {
+			return nil, err
+		}
+		m = local.Multiply(m)
+	}
+	return r.hitTest(root, m, utils.Point{X: x, Y: y}, map[*Element]bool{})
+}
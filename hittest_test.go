@@ -0,0 +1,108 @@
+package svgparser_test
+
+import (
+	"testing"
+
+	svgparser "github.com/chikamim/svgparser"
+)
+
+func TestHitTest(t *testing.T) {
+	svg := `
+		<svg width="200" height="200" viewBox="0 0 100 100">
+			<defs>
+				<symbol id="icon" viewBox="0 0 10 10"><circle cx="5" cy="5" r="5"/></symbol>
+			</defs>
+			<rect id="background" x="0" y="0" width="100" height="100" fill="white"/>
+			<g transform="translate(50 0)" style="fill:none">
+				<path id="ring" d="M0 10 H20 V30 H0 Z" stroke="black" stroke-width="4"/>
+				<path id="hole" d="M30 0 H50 V20 H30 Z M35 5 H45 V15 H35 Z" fill="red" fill-rule="evenodd"/>
+			</g>
+			<rect id="hidden" x="0" y="0" width="10" height="10" display="none"/>
+			<polyline id="line" points="0 90 40 90" stroke="blue" stroke-width="2" stroke-linecap="square"/>
+			<use id="instance" href="#icon" x="0" y="40" width="20" height="20"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	testCases := []struct {
+		x, y     float64
+		expected string
+	}{
+		// coordinates are in the root viewport, twice the viewBox
+		{10, 10, "background"},
+		{100, 20, "ring"},
+		{110, 40, "background"},
+		{162, 4, "hole"},
+		{180, 20, "background"},
+		{-2, 180, "line"},
+		{20, 100, "instance"},
+		{2, 82, "background"},
+		{300, 300, ""},
+	}
+
+	for _, test := range testCases {
+		hit, err := svgparser.HitTest(element, test.x, test.y)
+		if err != nil {
+			t.Fatalf("HitTest failed: %v\n", err)
+		}
+		actual := ""
+		if hit != nil {
+			actual = hit.Attributes["id"]
+		}
+		if actual != test.expected {
+			t.Errorf("HitTest (%v, %v): expected %q, actual %q\n", test.x, test.y, test.expected, actual)
+		}
+	}
+}
+
+func TestHitTestErrors(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<rect width="ten" height="10"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if hit, err := svgparser.HitTest(element, 5, 5); err == nil || err.Error() != `rect: invalid width "ten"` {
+		t.Errorf("HitTest: expected error %q, actual %v %v\n", `rect: invalid width "ten"`, hit, err)
+	}
+}
+
+func TestHitTestStyles(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<style>
+				.outline { fill: none; stroke: black; stroke-width: 4 }
+				#ghost { pointer-events: none }
+			</style>
+			<rect id="background" width="100" height="100"/>
+			<rect id="outline" class="outline" x="10" y="10" width="20" height="20" fill="red"/>
+			<rect id="ghost" x="40" y="10" width="20" height="20"/>
+			<rect id="hidden" x="70" y="10" width="20" height="20" visibility="hidden"/>
+			<rect id="all" x="10" y="70" width="20" height="20" visibility="hidden" style="pointer-events: all"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	testCases := []struct {
+		x, y     float64
+		expected string
+	}{
+		{20, 20, "background"},
+		{11, 20, "outline"},
+		{50, 20, "background"},
+		{80, 20, "background"},
+		{20, 80, "all"},
+	}
+
+	for _, test := range testCases {
+		hit, err := svgparser.HitTest(element, test.x, test.y)
+		if err != nil {
+			t.Fatalf("HitTest failed: %v\n", err)
+		}
+		actual := ""
+		if hit != nil {
+			actual = hit.Attributes["id"]
+		}
+		if actual != test.expected {
+			t.Errorf("HitTest (%v, %v): expected %q, actual %q\n", test.x, test.y, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package svgparser
+
+import (
+	"github.com/chikamim/svgparser/utils"
+)
+
+// lengthDirections lists the attributes whose percentages refer to the width
+// or height of the viewport. Percentages of all other lengths refer to its
+// normalized diagonal.
+var lengthDirections = map[string]utils.LengthDirection{
+	"x": utils.Horizontal, "cx": utils.Horizontal, "rx": utils.Horizontal, "x1": utils.Horizontal,
+	"x2": utils.Horizontal, "dx": utils.Horizontal, "width": utils.Horizontal, "refX": utils.Horizontal,
+	"y": utils.Vertical, "cy": utils.Vertical, "ry": utils.Vertical, "y1": utils.Vertical,
+	"y2": utils.Vertical, "dy": utils.Vertical, "height": utils.Vertical, "refY": utils.Vertical,
+}
+
+// lengthDirection returns the direction of percentages of an attribute.
+func lengthDirection(name string) utils.LengthDirection {
+	if d, ok := lengthDirections[name]; ok {
+		return d
+	}
+	return utils.Diagonal
+}
+
+// fontSize returns the font size of the element in user units, inherited
+// from its ancestors and 16 for the root element if it is not specified.
+// Font sizes are cached by the resolver.
+func (e *Element) fontSize(r *styleResolver, dpi float64) float64 {
+	key := fontSizeKey{e, dpi}
+	size, ok := r.fontSizes[key]
+	if !ok {
+		size = e.resolveFontSize(r, dpi)
+		r.fontSizes[key] = size
+	}
+	return size
+}
+
+// resolveFontSize computes the font size of the element. Declared font
+// sizes are taken from the cascade, and those which are not lengths, such as
+// keywords, are ignored.
+func (e *Element) resolveFontSize(r *styleResolver, dpi float64) float64 {
+	c := utils.LengthContext{DPI: dpi, FontSize: 16, RootFontSize: 16}
+	if e.Parent != nil {
+		c.FontSize = e.Parent.fontSize(r, dpi)
+		c.RootFontSize = e.root().fontSize(r, dpi)
+	}
+	v, ok := r.declared(e, "font-size")
+	if !ok {
+		return c.FontSize
+	}
+	l, err := utils.LengthParser(v)
+	if err != nil {
+		return c.FontSize
+	}
+	if l.Unit == "%" {
+		return c.FontSize * l.Value / 100
+	}
+	return l.Resolve(c, utils.Diagonal)
+}
+
+// viewportSize returns the size of the user space an <svg> element
+// establishes for its children: its viewBox if it has one, and its own
+// width and height otherwise. Missing sizes are 100% of the parent viewport.
+func (e *Element) viewportSize(r *styleResolver, dpi float64) (float64, float64) {
+	if vb, ok := e.viewBox(); ok {
+		return vb.Width, vb.Height
+	}
+	c := e.lengthContext(r, dpi)
+	size := []float64{c.ViewportWidth, c.ViewportHeight}
+	for i, name := range []string{"width", "height"} {
+		if _, ok := e.Attributes[name]; !ok {
+			continue
+		}
+		if l, err := e.length(name, false); err == nil {
+			size[i] = l.Resolve(c, lengthDirection(name))
+		}
+	}
+	return size[0], size[1]
+}
+
+// LengthContext returns the context for resolving the lengths of the
+// element into user units: the given DPI, where zero means 96, the font
+// size of the element and the size of the nearest viewport, which is
+// established by the closest <svg> ancestor. The root element has no
+// viewport, so percentages of its lengths resolve to zero.
+func (e *Element) LengthContext(dpi float64) utils.LengthContext {
+	return e.lengthContext(e.defaultStyles(), dpi)
+}
+
+// lengthContext returns the LengthContext of the element with font sizes
+// taken from the resolver.
+func (e *Element) lengthContext(r *styleResolver, dpi float64) utils.LengthContext {
+	if dpi == 0 {
+		dpi = 96
+	}
+	c := utils.LengthContext{DPI: dpi, FontSize: e.fontSize(r, dpi), RootFontSize: e.root().fontSize(r, dpi)}
+	for p := e.Parent; p != nil; p = p.Parent {
+		if p.Name == "svg" {
+			c.ViewportWidth, c.ViewportHeight = p.viewportSize(r, dpi)
+			break
+		}
+	}
+	return c
+}
+
+// ResolveLength returns the value of a length attribute in user units, such
+// as 793.7 for width="210mm" at 96 DPI. Percentages refer to the width of
+// the nearest viewport for horizontal attributes, to its height for vertical
+// attributes and to its normalized diagonal for others such as 'r'. Missing
+// attributes are zero, and invalid values give an error.
+func (e *Element) ResolveLength(name string, dpi float64) (float64, error) {
+	l, err := e.length(name, true)
+	if err != nil {
+		return 0, err
+	}
+	return l.Resolve(e.LengthContext(dpi), lengthDirection(name)), nil
+}
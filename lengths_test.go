@@ -0,0 +1,62 @@
+package svgparser_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	svgparser "github.com/chikamim/svgparser"
+)
+
+func TestResolveLength(t *testing.T) {
+	svg := `
+		<svg width="210mm" height="297mm" viewBox="0 0 300 400">
+			<g style="font-size: 20px">
+				<circle r="10%" cx="50%" cy="25%" />
+				<text x="2em" y="1rem" />
+			</g>
+			<svg x="10" width="50%" height="100">
+				<rect width="50%" height="1in" />
+			</svg>
+		</svg>
+	`
+	root, err := svgparser.Parse(strings.NewReader(svg), false)
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n", err)
+	}
+	g, nested := root.Children[0], root.Children[1]
+	circle, text, rect := g.Children[0], g.Children[1], nested.Children[0]
+
+	testCases := []struct {
+		element  *svgparser.Element
+		name     string
+		dpi      float64
+		expected float64
+	}{
+		{root, "width", 0, 793.7007874015749},
+		{root, "height", 72, 841.8897637795276},
+		{circle, "r", 0, math.Sqrt((300*300+400*400)/2.0) / 10},
+		{circle, "cx", 0, 150},
+		{circle, "cy", 0, 100},
+		{text, "x", 0, 40},
+		{text, "y", 0, 16},
+		{nested, "width", 0, 150},
+		{rect, "width", 0, 75},
+		{rect, "height", 0, 96},
+		{rect, "x", 0, 0},
+	}
+
+	for _, test := range testCases {
+		actual, err := test.element.ResolveLength(test.name, test.dpi)
+		if err != nil {
+			t.Errorf("ResolveLength %s %s failed: %v\n", test.element.Name, test.name, err)
+		} else if math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("ResolveLength %s %s: expected %v, actual %v\n", test.element.Name, test.name, test.expected, actual)
+		}
+	}
+
+	invalid := &svgparser.Element{Name: "rect", Attributes: map[string]string{"width": "10 furlongs"}}
+	if _, err := invalid.ResolveLength("width", 0); err == nil {
+		t.Errorf("ResolveLength: expected error for %q\n", invalid.Attributes["width"])
+	}
+}
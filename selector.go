@@ -0,0 +1,176 @@
+package svgparser
+
+import (
+	"strings"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// siblings returns the element's siblings before it, nearest first, and
+// after it.
+func (e *Element) siblings() ([]*Element, []*Element) {
+	if e.Parent == nil {
+		return nil, nil
+	}
+	children := e.Parent.Children
+	for i, c := range children {
+		if c == e {
+			before := make([]*Element, i)
+			for j := range before {
+				before[j] = children[i-1-j]
+			}
+			return before, children[i+1:]
+		}
+	}
+	return nil, nil
+}
+
+// matchesAttribute returns true if the element matches the attribute
+// selector.
+func (e *Element) matchesAttribute(a utils.AttributeSelector) bool {
+	v, ok := e.Attributes[a.Name]
+	if !ok {
+		return false
+	}
+	want := a.Value
+	if a.Insensitive {
+		v, want = strings.ToLower(v), strings.ToLower(want)
+	}
+	switch a.Operator {
+	case "":
+		return true
+	case "=":
+		return v == want
+	case "~=":
+		for _, f := range strings.Fields(v) {
+			if f == want {
+				return true
+			}
+		}
+		return false
+	case "|=":
+		return v == want || strings.HasPrefix(v, want+"-")
+	case "^=":
+		return want != "" && strings.HasPrefix(v, want)
+	case "$=":
+		return want != "" && strings.HasSuffix(v, want)
+	case "*=":
+		return want != "" && strings.Contains(v, want)
+	}
+	return false
+}
+
+// matchesPseudoClass returns true if the element matches the structural
+// pseudo-class. Dynamic pseudo-classes such as :hover never match.
+func (e *Element) matchesPseudoClass(name string) bool {
+	before, after := e.siblings()
+	ofType := func(elements []*Element) bool {
+		for _, s := range elements {
+			if s.Name == e.Name {
+				return false
+			}
+		}
+		return true
+	}
+	switch name {
+	case "root":
+		return e.Parent == nil
+	case "empty":
+		return len(e.Children) == 0 && e.Content == ""
+	case "first-child":
+		return e.Parent != nil && len(before) == 0
+	case "last-child":
+		return e.Parent != nil && len(after) == 0
+	case "only-child":
+		return e.Parent != nil && len(before) == 0 && len(after) == 0
+	case "first-of-type":
+		return e.Parent != nil && ofType(before)
+	case "last-of-type":
+		return e.Parent != nil && ofType(after)
+	case "only-of-type":
+		return e.Parent != nil && ofType(before) && ofType(after)
+	}
+	return false
+}
+
+// matchesCompound returns true if the element matches all simple selectors
+// of the compound selector.
+func (e *Element) matchesCompound(c utils.CompoundSelector) bool {
+	if c.PseudoElement != "" || c.Type != "" && c.Type != "*" && c.Type != e.Name {
+		return false
+	}
+	for _, id := range c.IDs {
+		if e.Attributes["id"] != id {
+			return false
+		}
+	}
+	classes := strings.Fields(e.Attributes["class"])
+	for _, class := range c.Classes {
+		found := false
+		for _, k := range classes {
+			found = found || k == class
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, a := range c.Attributes {
+		if !e.matchesAttribute(a) {
+			return false
+		}
+	}
+	for _, p := range c.PseudoClasses {
+		if !e.matchesPseudoClass(p) {
+			return false
+		}
+	}
+	for _, not := range c.Not {
+		for _, n := range not {
+			if e.matchesCompound(n) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesParts returns true if the element matches the last of the selector
+// parts and its relatives match the parts before it.
+func (e *Element) matchesParts(parts []utils.SelectorPart) bool {
+	last := parts[len(parts)-1]
+	if !e.matchesCompound(last.Compound) {
+		return false
+	}
+	rest := parts[:len(parts)-1]
+	if len(rest) == 0 {
+		return true
+	}
+	switch last.Combinator {
+	case ">":
+		return e.Parent != nil && e.Parent.matchesParts(rest)
+	case " ":
+		for p := e.Parent; p != nil; p = p.Parent {
+			if p.matchesParts(rest) {
+				return true
+			}
+		}
+	case "+":
+		before, _ := e.siblings()
+		return len(before) > 0 && before[0].matchesParts(rest)
+	case "~":
+		before, _ := e.siblings()
+		for _, s := range before {
+			if s.matchesParts(rest) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Matches returns true if the element is selected by the selector.
+// Namespaces are ignored and type selectors are case-sensitive, as in XML
+// documents.
+func (e *Element) Matches(s utils.Selector) bool {
+	return len(s.Parts) > 0 && e.matchesParts(s.Parts)
+}
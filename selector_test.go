@@ -0,0 +1,63 @@
+package svgparser_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestMatches(t *testing.T) {
+	svg := `
+		<svg>
+			<g id="group" class="icons">
+				<rect id="first" class="a b" data-name="Big-Box" />
+				<circle id="second" />
+				<rect id="third" />
+			</g>
+			<g id="empty" />
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	testCases := []struct {
+		selector string
+		id       string
+		expected bool
+	}{
+		{"rect", "first", true},
+		{"svg rect.a.b", "first", true},
+		{"svg > rect", "first", false},
+		{".icons > #first", "first", true},
+		{"rect.c", "first", false},
+		{"[data-name|=Big]", "first", true},
+		{"[data-name^=big i]", "first", true},
+		{"[data-name$=box]", "first", false},
+		{"rect:first-child", "first", true},
+		{"rect:last-child", "third", true},
+		{"rect:first-of-type", "third", false},
+		{"circle:only-of-type", "second", true},
+		{"#first + circle", "second", true},
+		{"#first ~ rect", "third", true},
+		{"#third ~ rect", "first", false},
+		{"rect:not(.a, #x)", "third", true},
+		{"rect:not(.a, #x)", "first", false},
+		{"g:empty", "empty", true},
+		{"g:empty", "group", false},
+		{"svg:root", "", true},
+		{"rect:hover", "first", false},
+	}
+
+	for _, test := range testCases {
+		selectors, err := utils.SelectorParser(test.selector)
+		if err != nil {
+			t.Errorf("SelectorParser %q failed: %v\n", test.selector, err)
+			continue
+		}
+		e := element
+		if test.id != "" {
+			e = element.FindID(test.id)
+		}
+		if actual := e.Matches(selectors[0]); actual != test.expected {
+			t.Errorf("Matches %q #%s: expected %v, actual %v\n", test.selector, test.id, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package svgparser
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// shapeAttributes lists the geometry attributes of the basic shapes which
+// are replaced by 'd' when a shape is converted to a path.
+var shapeAttributes = map[string][]string{
+	"rect":     {"x", "y", "width", "height", "rx", "ry"},
+	"circle":   {"cx", "cy", "r"},
+	"ellipse":  {"cx", "cy", "rx", "ry"},
+	"line":     {"x1", "y1", "x2", "y2"},
+	"polyline": {"points"},
+	"polygon":  {"points"},
+}
+
+// geometryNumber returns the value of a geometry attribute in user units at
+// 96 DPI, def if it is missing or auto, or an error if it is invalid or
+// negative where negative values are not allowed. Units and percentages are
+// resolved against the nearest viewport.
+func (e *Element) geometryNumber(r *styleResolver, name string, def float64, negative bool) (float64, error) {
+	if v, ok := e.Attributes[name]; !ok || strings.TrimSpace(v) == "auto" {
+		return def, nil
+	}
+	l, err := e.length(name, negative)
+	if err != nil {
+		return 0, err
+	}
+	return l.Resolve(e.lengthContext(r, 0), lengthDirection(name)), nil
+}
+
+// geometryNumbers returns the values of geometry attributes in user units,
+// which default to zero.
+func (e *Element) geometryNumbers(r *styleResolver, negative bool, names ...string) ([]float64, error) {
+	numbers := make([]float64, len(names))
+	for i, name := range names {
+		n, err := e.geometryNumber(r, name, 0, negative)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+// pathBuilder collects absolute commands into a single subpath.
+type pathBuilder struct {
+	subpath utils.Subpath
+}
+
+func (b *pathBuilder) add(symbol string, params ...float64) {
+	if params == nil {
+		params = []float64{}
+	}
+	b.subpath.Commands = append(b.subpath.Commands, &utils.Command{Symbol: symbol, Params: params})
+}
+
+func (b *pathBuilder) arc(rx, ry, x, y float64) {
+	b.add("A", rx, ry, 0, 0, 1, x, y)
+}
+
+func (b *pathBuilder) path() *utils.Path {
+	if len(b.subpath.Commands) == 0 {
+		return &utils.Path{}
+	}
+	return &utils.Path{Subpaths: []*utils.Subpath{&b.subpath}}
+}
+
+// rectPath returns the path of a rect. A missing rx or ry takes the value of
+// the other, and both are clamped to half the width and height.
+func (e *Element) rectPath(r *styleResolver) (*utils.Path, error) {
+	n, err := e.geometryNumbers(r, true, "x", "y")
+	if err != nil {
+		return nil, err
+	}
+	size, err := e.geometryNumbers(r, false, "width", "height")
+	if err != nil {
+		return nil, err
+	}
+	x, y, w, h := n[0], n[1], size[0], size[1]
+	rx, err := e.geometryNumber(r, "rx", -1, false)
+	if err != nil {
+		return nil, err
+	}
+	ry, err := e.geometryNumber(r, "ry", -1, false)
+	if err != nil {
+		return nil, err
+	}
+	b := &pathBuilder{}
+	if w == 0 || h == 0 {
+		return b.path(), nil
+	}
+	if rx < 0 {
+		rx = ry
+	}
+	if ry < 0 {
+		ry = rx
+	}
+	rx, ry = math.Max(0, math.Min(rx, w/2)), math.Max(0, math.Min(ry, h/2))
+	if rx == 0 || ry == 0 {
+		b.add("M", x, y)
+		b.add("H", x+w)
+		b.add("V", y+h)
+		b.add("H", x)
+		b.add("Z")
+		return b.path(), nil
+	}
+	b.add("M", x+rx, y)
+	b.add("H", x+w-rx)
+	b.arc(rx, ry, x+w, y+ry)
+	b.add("V", y+h-ry)
+	b.arc(rx, ry, x+w-rx, y+h)
+	b.add("H", x+rx)
+	b.arc(rx, ry, x, y+h-ry)
+	b.add("V", y+ry)
+	b.arc(rx, ry, x+rx, y)
+	b.add("Z")
+	return b.path(), nil
+}
+
+// ellipsePath returns the path of a circle or ellipse, which starts at the
+// right end of the horizontal axis and runs clockwise in four arcs. A
+// missing rx or ry of an ellipse takes the value of the other.
+func (e *Element) ellipsePath(r *styleResolver) (*utils.Path, error) {
+	c, err := e.geometryNumbers(r, true, "cx", "cy")
+	if err != nil {
+		return nil, err
+	}
+	var rx, ry float64
+	if e.Name == "circle" {
+		if rx, err = e.geometryNumber(r, "r", 0, false); err != nil {
+			return nil, err
+		}
+		ry = rx
+	} else {
+		if rx, err = e.geometryNumber(r, "rx", -1, false); err != nil {
+			return nil, err
+		}
+		if ry, err = e.geometryNumber(r, "ry", -1, false); err != nil {
+			return nil, err
+		}
+		if rx < 0 {
+			rx = ry
+		}
+		if ry < 0 {
+			ry = rx
+		}
+	}
+	b := &pathBuilder{}
+	if rx <= 0 || ry <= 0 {
+		return b.path(), nil
+	}
+	cx, cy := c[0], c[1]
+	b.add("M", cx+rx, cy)
+	b.arc(rx, ry, cx, cy+ry)
+	b.arc(rx, ry, cx-rx, cy)
+	b.arc(rx, ry, cx, cy-ry)
+	b.arc(rx, ry, cx+rx, cy)
+	b.add("Z")
+	return b.path(), nil
+}
+
+// pointsPath returns the path of a polyline or polygon. An odd number of
+// coordinates ignores the last one, like renderers do.
+func (e *Element) pointsPath() (*utils.Path, error) {
+	numbers, err := utils.NumberListParser(e.Attributes["points"])
+	if err != nil {
+		return nil, ValidationError{fmt.Sprintf("%s: invalid points %q", e.Name, e.Attributes["points"])}
+	}
+	b := &pathBuilder{}
+	for i := 0; i+1 < len(numbers); i += 2 {
+		symbol := "L"
+		if i == 0 {
+			symbol = "M"
+		}
+		b.add(symbol, numbers[i], numbers[i+1])
+	}
+	if e.Name == "polygon" && len(numbers) >= 2 {
+		b.add("Z")
+	}
+	return b.path(), nil
+}
+
+// Path returns the geometry of a path or basic shape element as a path, so
+// that all shapes can be treated uniformly. Basic shapes follow the
+// equivalent paths of the SVG specification, including the automatic rx
+// and ry of rects and ellipses, and shapes which are not rendered because of
+// a zero size give an empty path. Lengths with units and percentages are
+// resolved into user units at 96 DPI, and the odd last coordinate of a
+// polyline or polygon is ignored. Invalid or negative attribute values give
+// an error. For invalid path data the path up to the error is returned
+// together with the error.
+func (e *Element) Path() (*utils.Path, error) {
+	return e.path(e.defaultStyles())
+}
+
+// path returns the Path of the element with font sizes taken from the
+// resolver.
+func (e *Element) path(r *styleResolver) (*utils.Path, error) {
+	switch e.Name {
+	case "path":
+		return utils.PathParserRecover(e.Attributes["d"])
+	case "rect":
+		return e.rectPath(r)
+	case "circle", "ellipse":
+		return e.ellipsePath(r)
+	case "line":
+		n, err := e.geometryNumbers(r, true, "x1", "y1", "x2", "y2")
+		if err != nil {
+			return nil, err
+		}
+		b := &pathBuilder{}
+		b.add("M", n[0], n[1])
+		b.add("L", n[2], n[3])
+		return b.path(), nil
+	case "polyline", "polygon":
+		return e.pointsPath()
+	}
+	return nil, ValidationError{e.Name + " is not a shape"}
+}
+
+// ConvertToPath rewrites a basic shape element in place as an equivalent
+// <path> element. The geometry attributes are replaced by 'd' and all other
+// attributes are kept. Path elements are left unchanged.
+func (e *Element) ConvertToPath() error {
+	if e.Name == "path" {
+		return nil
+	}
+	path, err := e.Path()
+	if err != nil {
+		return err
+	}
+	for _, k := range shapeAttributes[e.Name] {
+		delete(e.Attributes, k)
+	}
+	e.Name = "path"
+	e.Attributes["d"] = path.String()
+	return nil
+}
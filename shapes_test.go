@@ -0,0 +1,113 @@
+package svgparser_test
+
+import (
+	"math"
+	"testing"
+
+	svgparser "github.com/chikamim/svgparser"
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestElementPath(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attributes map[string]string
+		expected   string
+	}{
+		{"rect", map[string]string{"x": "1", "y": "2", "width": "10", "height": "20"}, "M 1 2 H 11 V 22 H 1 Z"},
+		{"rect", map[string]string{"width": "10", "height": "20", "rx": "2"},
+			"M 2 0 H 8 A 2 2 0 0 1 10 2 V 18 A 2 2 0 0 1 8 20 H 2 A 2 2 0 0 1 0 18 V 2 A 2 2 0 0 1 2 0 Z"},
+		{"rect", map[string]string{"width": "10", "height": "20", "rx": "auto", "ry": "30"},
+			"M 5 0 H 5 A 5 10 0 0 1 10 10 V 10 A 5 10 0 0 1 5 20 H 5 A 5 10 0 0 1 0 10 V 10 A 5 10 0 0 1 5 0 Z"},
+		{"rect", map[string]string{"width": "0", "height": "20"}, ""},
+		{"circle", map[string]string{"cx": "10", "cy": "10", "r": "5"},
+			"M 15 10 A 5 5 0 0 1 10 15 A 5 5 0 0 1 5 10 A 5 5 0 0 1 10 5 A 5 5 0 0 1 15 10 Z"},
+		{"ellipse", map[string]string{"rx": "4", "ry": "2"},
+			"M 4 0 A 4 2 0 0 1 0 2 A 4 2 0 0 1 -4 0 A 4 2 0 0 1 0 -2 A 4 2 0 0 1 4 0 Z"},
+		{"ellipse", map[string]string{"ry": "2"},
+			"M 2 0 A 2 2 0 0 1 0 2 A 2 2 0 0 1 -2 0 A 2 2 0 0 1 0 -2 A 2 2 0 0 1 2 0 Z"},
+		{"line", map[string]string{"x1": "1", "y1": "2", "x2": "3", "y2": "4"}, "M 1 2 L 3 4"},
+		{"polyline", map[string]string{"points": "0,0 10,0 10,10 5"}, "M 0 0 L 10 0 L 10 10"},
+		{"polygon", map[string]string{"points": "0,0 10,0 10,10"}, "M 0 0 L 10 0 L 10 10 Z"},
+		{"path", map[string]string{"d": "m0 0 h10"}, "m 0 0 h 10"},
+	}
+
+	for _, test := range testCases {
+		e := &svgparser.Element{Name: test.name, Attributes: test.attributes}
+		path, err := e.Path()
+		if err != nil {
+			t.Errorf("Path %s %v failed: %v\n", test.name, test.attributes, err)
+			continue
+		}
+		if actual := path.String(); actual != test.expected {
+			t.Errorf("Path %s %v: expected %q, actual %q\n", test.name, test.attributes, test.expected, actual)
+		}
+	}
+}
+
+func TestElementPathLengths(t *testing.T) {
+	element, _ := parse(`
+		<svg width="200" height="100">
+			<rect width="50%" height="10mm"/>
+			<circle cx="1in" cy="50%" r="10%"/>
+		</svg>
+	`, false)
+	mm := 96 / 25.4
+	r := math.Sqrt((200*200+100*100)/2.0) / 10
+	testCases := []struct {
+		element  *svgparser.Element
+		min, max utils.Point
+	}{
+		{element.Children[0], utils.Point{X: 0, Y: 0}, utils.Point{X: 100, Y: 10 * mm}},
+		{element.Children[1], utils.Point{X: 96 - r, Y: 50 - r}, utils.Point{X: 96 + r, Y: 50 + r}},
+	}
+
+	for _, test := range testCases {
+		path, err := test.element.Path()
+		if err != nil {
+			t.Errorf("Path %s failed: %v\n", test.element.Name, err)
+			continue
+		}
+		if b := path.Bounds(); !closePoints(b.Min, test.min) || !closePoints(b.Max, test.max) {
+			t.Errorf("Path %s: expected bounds %v %v, actual %v %v\n", test.element.Name, test.min, test.max, b.Min, b.Max)
+		}
+	}
+}
+
+func TestElementPathErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attributes map[string]string
+		expected   string
+	}{
+		{"rect", map[string]string{"width": "ten", "height": "20"}, `rect: invalid width "ten"`},
+		{"circle", map[string]string{"r": "-1"}, `circle: negative r "-1"`},
+		{"polygon", map[string]string{"points": "0,0 a,b"}, `polygon: invalid points "0,0 a,b"`},
+		{"g", map[string]string{}, "g is not a shape"},
+	}
+
+	for _, test := range testCases {
+		e := &svgparser.Element{Name: test.name, Attributes: test.attributes}
+		if _, err := e.Path(); err == nil || err.Error() != test.expected {
+			t.Errorf("Path %s %v: expected error %q, actual %v\n", test.name, test.attributes, test.expected, err)
+		}
+	}
+}
+
+func TestConvertToPath(t *testing.T) {
+	e := &svgparser.Element{Name: "rect", Attributes: map[string]string{
+		"id": "box", "x": "1", "y": "2", "width": "3", "height": "4", "fill": "red",
+	}}
+	if err := e.ConvertToPath(); err != nil {
+		t.Fatalf("ConvertToPath failed: %v\n", err)
+	}
+	expected := map[string]string{"id": "box", "fill": "red", "d": "M 1 2 H 4 V 6 H 1 Z"}
+	if e.Name != "path" || len(e.Attributes) != len(expected) {
+		t.Errorf("ConvertToPath: expected path with %v, actual %v %v\n", expected, e.Name, e.Attributes)
+	}
+	for k, v := range expected {
+		if e.Attributes[k] != v {
+			t.Errorf("ConvertToPath %s: expected %q, actual %q\n", k, v, e.Attributes[k])
+		}
+	}
+}
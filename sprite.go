@@ -0,0 +1,254 @@
+package svgparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Icon is a standalone SVG document identified by the id of its symbol in a
+// sprite.
+type Icon struct {
+	ID       string
+	Document *Element
+}
+
+// attributes of an icon root which are not carried over to its symbol.
+var documentOnlyAttributes = map[string]bool{
+	"id": true, "x": true, "y": true, "width": true, "height": true,
+	"viewBox": true, "version": true, "baseProfile": true,
+}
+
+func isNamespaceAttribute(key string) bool {
+	return key == "xmlns" || strings.HasPrefix(key, "xmlns:")
+}
+
+func isGradient(e *Element) bool {
+	return e.Name == "linearGradient" || e.Name == "radialGradient"
+}
+
+// newElement creates an element with the given name and attributes.
+func newElement(name string, attributes map[string]string) *Element {
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	element := &Element{Name: name, Attributes: attributes}
+	element.UUID = element.Hash()
+	return element
+}
+
+// appendChild adds child as the last child of the element.
+func (e *Element) appendChild(child *Element) {
+	child.Parent = e
+	e.Children = append(e.Children, child)
+}
+
+// removeChild detaches child from the element.
+func (e *Element) removeChild(child *Element) {
+	for i, c := range e.Children {
+		if c == child {
+			e.Children = append(e.Children[:i], e.Children[i+1:]...)
+			child.Parent = nil
+			return
+		}
+	}
+}
+
+// documentViewBox returns the viewBox of a document root, derived from its
+// width and height when the viewBox attribute is missing.
+func documentViewBox(root *Element) string {
+	if vb, ok := root.Attributes["viewBox"]; ok {
+		return vb
+	}
+	w, errw := parseNumber(root.Attributes["width"])
+	h, errh := parseNumber(root.Attributes["height"])
+	if errw != nil || errh != nil {
+		return ""
+	}
+	return fmt.Sprintf("0 0 %v %v", w, h)
+}
+
+// canonical returns a serialization of the element subtree which ignores the
+// element's own id and the order of attributes.
+func canonical(e *Element, root bool) string {
+	var keys []string
+	for k := range e.Attributes {
+		if !(root && k == "id") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("<" + e.Name)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%q", k, e.Attributes[k])
+	}
+	b.WriteString(">" + e.Content)
+	for _, child := range e.Children {
+		b.WriteString(canonical(child, false))
+	}
+	b.WriteString("</" + e.Name + ">")
+	return b.String()
+}
+
+// uniqueID returns id, or a prefixed and numbered variant of it which is not
+// in used yet, and marks the result as used.
+func uniqueID(used map[string]bool, prefix, id string) string {
+	candidate := id
+	if used[candidate] {
+		candidate = prefix + "-" + id
+	}
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%s-%d", prefix, id, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// BuildSprite combines icon documents into a single sprite document of
+// <symbol> elements. Every symbol carries the viewBox of its source root. Ids
+// colliding between icons are renamed, and gradients are hoisted into a
+// common <defs> where identical gradients are shared.
+func BuildSprite(icons []Icon) (*Element, error) {
+	sprite := newElement("svg", map[string]string{"xmlns": "http://www.w3.org/2000/svg"})
+	defs := newElement("defs", nil)
+	used := map[string]bool{}
+	gradients := map[string]string{}
+
+	for _, icon := range icons {
+		id := icon.ID
+		if id == "" {
+			id = icon.Document.Attributes["id"]
+		}
+		if id == "" {
+			return nil, ValidationError{"sprite icon without id"}
+		}
+		if used[id] {
+			return nil, ValidationError{"duplicate sprite icon id " + id}
+		}
+		used[id] = true
+	}
+
+	for _, icon := range icons {
+		id := icon.ID
+		if id == "" {
+			id = icon.Document.Attributes["id"]
+		}
+		doc := icon.Document.Clone()
+
+		renamed := map[string]string{}
+		for _, d := range doc.Descendants() {
+			if old, ok := d.Attributes["id"]; ok {
+				n := uniqueID(used, id, old)
+				d.Attributes["id"] = n
+				if n != old {
+					renamed[old] = n
+				}
+			}
+		}
+		renameReferences(doc, renamed)
+
+		for _, g := range doc.Descendants() {
+			if !isGradient(g) {
+				continue
+			}
+			key := canonical(g, true)
+			g.Parent.removeChild(g)
+			if shared, ok := gradients[key]; ok {
+				renameReferences(doc, map[string]string{g.Attributes["id"]: shared})
+				renameReferences(defs, map[string]string{g.Attributes["id"]: shared})
+				continue
+			}
+			if gid, ok := g.Attributes["id"]; ok {
+				gradients[key] = gid
+			}
+			defs.appendChild(g)
+		}
+		for _, d := range doc.FindAll("defs") {
+			if len(d.Children) == 0 && d.Content == "" {
+				d.Parent.removeChild(d)
+			}
+		}
+
+		symbol := newElement("symbol", map[string]string{"id": id})
+		if vb := documentViewBox(icon.Document); vb != "" {
+			symbol.Attributes["viewBox"] = vb
+		}
+		for k, v := range doc.Attributes {
+			if isNamespaceAttribute(k) {
+				if k != "xmlns" {
+					sprite.Attributes[k] = v
+				}
+				continue
+			}
+			if !documentOnlyAttributes[k] {
+				symbol.Attributes[k] = v
+			}
+		}
+		for _, child := range doc.Children {
+			symbol.appendChild(child)
+		}
+		sprite.appendChild(symbol)
+	}
+
+	if len(defs.Children) > 0 {
+		defs.Parent = sprite
+		sprite.Children = append([]*Element{defs}, sprite.Children...)
+	}
+	return sprite, nil
+}
+
+// SplitSprite splits a sprite into standalone documents, one for every
+// <symbol>. Elements referenced from outside the symbol, such as shared
+// gradients, are copied into the <defs> of each document that uses them.
+func SplitSprite(sprite *Element) []Icon {
+	var icons []Icon
+	for _, symbol := range sprite.FindAll("symbol") {
+		doc := newElement("svg", map[string]string{"xmlns": "http://www.w3.org/2000/svg"})
+		for k, v := range sprite.Attributes {
+			if isNamespaceAttribute(k) {
+				doc.Attributes[k] = v
+			}
+		}
+		for k, v := range symbol.Attributes {
+			if k != "id" {
+				doc.Attributes[k] = v
+			}
+		}
+		for _, child := range symbol.Children {
+			doc.appendChild(child.Clone())
+		}
+
+		defs := newElement("defs", nil)
+		copied := map[string]bool{}
+		for _, d := range doc.Descendants() {
+			if id, ok := d.Attributes["id"]; ok {
+				copied[id] = true
+			}
+		}
+		pending := []*Element{doc}
+		for len(pending) > 0 {
+			e := pending[0]
+			pending = pending[1:]
+			for _, d := range append([]*Element{e}, e.Descendants()...) {
+				for _, ref := range d.referencedIDs() {
+					if copied[ref] {
+						continue
+					}
+					copied[ref] = true
+					if target := sprite.FindID(ref); target != nil {
+						c := target.Clone()
+						defs.appendChild(c)
+						pending = append(pending, c)
+					}
+				}
+			}
+		}
+		if len(defs.Children) > 0 {
+			defs.Parent = doc
+			doc.Children = append([]*Element{defs}, doc.Children...)
+		}
+		icons = append(icons, Icon{ID: symbol.Attributes["id"], Document: doc})
+	}
+	return icons
+}
@@ -0,0 +1,93 @@
+package svgparser_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser"
+)
+
+func testIcons() []svgparser.Icon {
+	star, _ := parse(`
+		<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" fill="none">
+			<defs>
+				<linearGradient id="g"><stop offset="0" stop-color="red"/></linearGradient>
+			</defs>
+			<path id="shape" d="M0 0 L24 24" fill="url(#g)"/>
+		</svg>
+	`, false)
+	heart, _ := parse(`
+		<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16">
+			<defs>
+				<linearGradient id="fade"><stop offset="0" stop-color="red"/></linearGradient>
+			</defs>
+			<path id="shape" d="M0 0 L16 16" fill="url(#fade)"/>
+			<use xlink:href="#shape"/>
+		</svg>
+	`, false)
+	return []svgparser.Icon{{ID: "star", Document: star}, {ID: "heart", Document: heart}}
+}
+
+func TestBuildSprite(t *testing.T) {
+	sprite, err := svgparser.BuildSprite(testIcons())
+	if err != nil {
+		t.Fatalf("BuildSprite failed: %v\n", err)
+	}
+
+	symbols := sprite.FindAll("symbol")
+	if len(symbols) != 2 {
+		t.Fatalf("BuildSprite: expected %v symbols, actual %v\n", 2, len(symbols))
+	}
+	if vb := symbols[0].Attributes["viewBox"]; vb != "0 0 24 24" {
+		t.Errorf("BuildSprite: expected viewBox %v, actual %v\n", "0 0 24 24", vb)
+	}
+	if vb := symbols[1].Attributes["viewBox"]; vb != "0 0 16 16" {
+		t.Errorf("BuildSprite: expected viewBox %v, actual %v\n", "0 0 16 16", vb)
+	}
+	if fill := symbols[0].Attributes["fill"]; fill != "none" {
+		t.Errorf("BuildSprite: expected fill %v, actual %v\n", "none", fill)
+	}
+
+	gradients := sprite.FindAll("linearGradient")
+	if len(gradients) != 1 || gradients[0].Parent != sprite.Children[0] {
+		t.Errorf("BuildSprite: expected one hoisted gradient, actual %v\n", gradients)
+	}
+
+	paths := sprite.FindAll("path")
+	if paths[0].Attributes["id"] == paths[1].Attributes["id"] {
+		t.Errorf("BuildSprite: duplicate id %v\n", paths[0].Attributes["id"])
+	}
+	if paths[1].Attributes["fill"] != "url(#g)" {
+		t.Errorf("BuildSprite: expected fill %v, actual %v\n", "url(#g)", paths[1].Attributes["fill"])
+	}
+	use := sprite.FindAll("use")[0]
+	if use.Attributes["xlink:href"] != "#"+paths[1].Attributes["id"] {
+		t.Errorf("BuildSprite: expected href %v, actual %v\n", "#"+paths[1].Attributes["id"], use.Attributes["xlink:href"])
+	}
+}
+
+func TestBuildSpriteDuplicateID(t *testing.T) {
+	icons := testIcons()
+	icons[1].ID = "star"
+	if _, err := svgparser.BuildSprite(icons); err == nil {
+		t.Error("BuildSprite: expected error for duplicate icon id")
+	}
+}
+
+func TestSplitSprite(t *testing.T) {
+	sprite, _ := svgparser.BuildSprite(testIcons())
+	icons := svgparser.SplitSprite(sprite)
+	if len(icons) != 2 {
+		t.Fatalf("SplitSprite: expected %v icons, actual %v\n", 2, len(icons))
+	}
+	if icons[1].ID != "heart" {
+		t.Errorf("SplitSprite: expected id %v, actual %v\n", "heart", icons[1].ID)
+	}
+
+	doc := icons[1].Document
+	if doc.Name != "svg" || doc.Attributes["viewBox"] != "0 0 16 16" {
+		t.Errorf("SplitSprite: unexpected root %v\n", doc)
+	}
+	if doc.FindID("g") == nil {
+		t.Error("SplitSprite: referenced gradient not copied")
+	}
+}
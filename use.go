@@ -0,0 +1,177 @@
+package svgparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// attributes of <use> which are consumed when it is expanded.
+var useOnlyAttributes = map[string]bool{
+	"x": true, "y": true, "width": true, "height": true,
+	"href": true, "xlink:href": true, "transform": true,
+}
+
+// attributes of <symbol> which are consumed when it is instantiated.
+var symbolOnlyAttributes = map[string]bool{
+	"id": true, "x": true, "y": true, "width": true, "height": true,
+	"viewBox": true, "preserveAspectRatio": true, "refX": true, "refY": true,
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// root returns the outermost ancestor of the element.
+func (e *Element) root() *Element {
+	r := e
+	for r.Parent != nil {
+		r = r.Parent
+	}
+	return r
+}
+
+// stripIDs removes the id attributes of the element and its descendants.
+func stripIDs(e *Element) {
+	delete(e.Attributes, "id")
+	for _, child := range e.Children {
+		stripIDs(child)
+	}
+}
+
+// ExpandUses replaces every <use> element within the element with a <g>
+// holding a deep copy of the referenced element. The x and y offset of the
+// <use> is applied as translation, and the viewBox of a referenced <symbol>
+// is translated into a transform. Nested references are expanded as well,
+// while <use> elements without a reference within the document, such as
+// href="other.svg#icon", are left in place. All references are checked before anything is replaced, so the element is
+// left unchanged if an error is returned for cyclic or missing references.
+func (e *Element) ExpandUses() error {
+	uses := e.FindAll("use")
+	for _, use := range uses {
+		if err := checkUse(use, map[string]bool{}); err != nil {
+			return err
+		}
+	}
+	for _, use := range uses {
+		// uses inside an already expanded use have been replaced already
+		if use.root() != e.root() {
+			continue
+		}
+		if err := expandUse(use, map[string]bool{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// useTarget returns the element referenced by the use element, or an error
+// if it is missing or already being expanded.
+func useTarget(use *Element, visiting map[string]bool) (*Element, error) {
+	id := use.hrefID()
+	if visiting[id] {
+		return nil, ValidationError{"cyclic use reference #" + id}
+	}
+	target := use.root().FindID(id)
+	if target == nil {
+		return nil, ValidationError{"use references missing element #" + id}
+	}
+	return target, nil
+}
+
+// checkUse returns the error which expanding the use element would give,
+// following nested references without changing the tree.
+func checkUse(use *Element, visiting map[string]bool) error {
+	if use.hrefID() == "" {
+		return nil
+	}
+	target, err := useTarget(use, visiting)
+	if err != nil {
+		return err
+	}
+	id := use.hrefID()
+	visiting[id] = true
+	defer delete(visiting, id)
+	nested := target.FindAll("use")
+	if target.Name == "use" {
+		nested = append(nested, target)
+	}
+	for _, n := range nested {
+		if err := checkUse(n, visiting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expandUse(use *Element, visiting map[string]bool) error {
+	id := use.hrefID()
+	if id == "" {
+		return nil
+	}
+	target, err := useTarget(use, visiting)
+	if err != nil {
+		return err
+	}
+
+	group := newElement("g", nil)
+	for k, v := range use.Attributes {
+		if !useOnlyAttributes[k] {
+			group.Attributes[k] = v
+		}
+	}
+	var transform []string
+	if t, ok := use.Attributes["transform"]; ok {
+		transform = append(transform, t)
+	}
+	// expanding changes the document, so styles are resolved for each use
+	r := use.defaultStyles()
+	x, y := use.attributeLength(r, "x", 0), use.attributeLength(r, "y", 0)
+	if x != 0 || y != 0 {
+		transform = append(transform, "translate("+formatNumber(x)+","+formatNumber(y)+")")
+	}
+	if len(transform) > 0 {
+		group.Attributes["transform"] = strings.Join(transform, " ")
+	}
+
+	var content *Element
+	if target.Name == "symbol" {
+		content = newElement("g", nil)
+		for k, v := range target.Attributes {
+			if !symbolOnlyAttributes[k] {
+				content.Attributes[k] = v
+			}
+		}
+		if t := symbolTransform(r, target, use); !t.IsIdentity() {
+			content.Attributes["transform"] = t.String()
+		}
+		for _, child := range target.Children {
+			content.appendChild(child.Clone())
+		}
+	} else {
+		content = target.Clone()
+	}
+	stripIDs(content)
+	group.appendChild(content)
+
+	parent := use.Parent
+	for i, c := range parent.Children {
+		if c == use {
+			parent.Children[i] = group
+			group.Parent = parent
+			use.Parent = nil
+			break
+		}
+	}
+
+	visiting[id] = true
+	defer delete(visiting, id)
+	for _, nested := range content.FindAll("use") {
+		if err := expandUse(nested, visiting); err != nil {
+			return err
+		}
+	}
+	if content.Name == "use" {
+		return expandUse(content, visiting)
+	}
+	return nil
+}
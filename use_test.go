@@ -0,0 +1,98 @@
+package svgparser_test
+
+import (
+	"testing"
+)
+
+func TestExpandUses(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<defs>
+				<rect id="box" width="10" height="10"/>
+				<symbol id="icon" viewBox="0 0 10 10">
+					<use xlink:href="#box"/>
+				</symbol>
+			</defs>
+			<use id="first" xlink:href="#box" x="5" y="6" fill="red"/>
+			<use xlink:href="#icon" width="20" height="20" transform="rotate(45)"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.ExpandUses(); err != nil {
+		t.Fatalf("ExpandUses failed: %v\n", err)
+	}
+	if uses := element.FindAll("use"); len(uses) != 0 {
+		t.Errorf("ExpandUses: expected no use elements, actual %v\n", len(uses))
+	}
+
+	first := element.FindID("first")
+	if first.Name != "g" || first.Attributes["transform"] != "translate(5,6)" || first.Attributes["fill"] != "red" {
+		t.Errorf("ExpandUses: unexpected group %v\n", first)
+	}
+	if rect := first.Children[0]; rect.Name != "rect" || rect.Attributes["id"] != "" {
+		t.Errorf("ExpandUses: unexpected clone %v\n", rect)
+	}
+
+	second := element.Children[2]
+	if second.Attributes["transform"] != "rotate(45)" {
+		t.Errorf("ExpandUses: expected transform %v, actual %v\n", "rotate(45)", second.Attributes["transform"])
+	}
+	symbol := second.Children[0]
+	if symbol.Attributes["transform"] != "scale(2)" {
+		t.Errorf("ExpandUses: expected transform %v, actual %v\n", "scale(2)", symbol.Attributes["transform"])
+	}
+	if len(symbol.FindAll("rect")) != 1 {
+		t.Error("ExpandUses: nested use not expanded")
+	}
+}
+
+func TestExpandUsesCycle(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<g id="a"><use xlink:href="#b"/></g>
+			<g id="b"><use xlink:href="#a"/></g>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.ExpandUses(); err == nil {
+		t.Error("ExpandUses: expected error for cyclic reference")
+	}
+}
+
+func TestExpandUsesUnchangedOnError(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<rect id="r" width="10" height="10"/>
+			<use xlink:href="#r"/>
+			<use xlink:href="#missing"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.ExpandUses(); err == nil {
+		t.Error("ExpandUses: expected error for missing reference")
+	}
+	if uses := element.FindAll("use"); len(uses) != 2 {
+		t.Errorf("ExpandUses: expected 2 use elements, actual %v\n", len(uses))
+	}
+	if groups := element.FindAll("g"); len(groups) != 0 {
+		t.Errorf("ExpandUses: expected no groups, actual %v\n", len(groups))
+	}
+}
+
+func TestExpandUsesExternal(t *testing.T) {
+	svg := `
+		<svg width="100" height="100">
+			<rect id="r" width="10" height="10"/>
+			<use id="external" xlink:href="other.svg#r"/>
+			<g id="g"><use xlink:href="#r"/></g>
+			<use xlink:href="#g"/>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.ExpandUses(); err != nil {
+		t.Fatalf("ExpandUses failed: %v\n", err)
+	}
+	if uses := element.FindAll("use"); len(uses) != 1 || uses[0].Attributes["id"] != "external" {
+		t.Errorf("ExpandUses: expected only the external use, actual %v\n", uses)
+	}
+}
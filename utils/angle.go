@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AngleParserError contains errors which have occured when parsing an
+// angle.
+type AngleParserError struct {
+	msg string
+}
+
+func (err AngleParserError) Error() string {
+	return err.msg
+}
+
+// angleUnits lists the units accepted for angles with their size in degrees.
+var angleUnits = map[string]float64{
+	"": 1, "deg": 1, "rad": 180 / math.Pi, "grad": 0.9, "turn": 360,
+}
+
+// AngleParser parses an angle, i.e. a number optionally followed by one of
+// the units deg, rad, grad and turn without whitespace in between, and
+// returns it in degrees. Numbers without unit are degrees.
+func AngleParser(raw string) (float64, error) {
+	s := strings.TrimSpace(raw)
+	n := scanNumber(s)
+	if n == 0 {
+		return 0, AngleParserError{"invalid angle " + strconv.Quote(raw)}
+	}
+	value, err := strconv.ParseFloat(s[:n], 64)
+	if err != nil {
+		return 0, AngleParserError{"invalid angle " + strconv.Quote(raw)}
+	}
+	unit, ok := angleUnits[strings.ToLower(s[n:])]
+	if !ok {
+		return 0, AngleParserError{"unknown unit in angle " + strconv.Quote(raw)}
+	}
+	return value * unit, nil
+}
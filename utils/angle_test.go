@@ -0,0 +1,36 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestAngleParser(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected float64
+	}{
+		{"45", 45},
+		{" -90deg ", -90},
+		{"3.14159265358979rad", 180},
+		{"100grad", 90},
+		{".25TURN", 90},
+	}
+
+	for _, test := range testCases {
+		actual, err := utils.AngleParser(test.raw)
+		if err != nil {
+			t.Errorf("AngleParser %q failed: %v\n", test.raw, err)
+		} else if math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("AngleParser %q: expected %v, actual %v\n", test.raw, test.expected, actual)
+		}
+	}
+
+	for _, raw := range []string{"", "deg", "10 deg", "10px", "auto"} {
+		if _, err := utils.AngleParser(raw); err == nil {
+			t.Errorf("AngleParser %q: expected error\n", raw)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package utils
+
+import "math"
+
+// Arc is an elliptical arc in center parameterization. Angles are in
+// degrees; a positive Sweep runs in the direction of positive angles.
+type Arc struct {
+	Center   Point
+	RX, RY   float64
+	Rotation float64
+	Start    float64
+	Sweep    float64
+}
+
+// vectorAngle returns the angle in radians from vector u to vector v.
+func vectorAngle(ux, uy, vx, vy float64) float64 {
+	return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+}
+
+// EndpointToCenter converts an arc from the endpoint parameterization used by
+// the 'A' command to center parameterization, following the SVG
+// Implementation Notes F.6.5. Radii which are too small to reach the end
+// point are scaled up as described in F.6.6. It returns false if the arc is
+// rendered as a straight line or omitted because rx or ry is zero or both
+// end points are equal.
+func EndpointToCenter(from Point, rx, ry, rotation float64, largeArc, sweep bool, to Point) (Arc, bool) {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || from == to {
+		return Arc{}, false
+	}
+	sin, cos := math.Sincos(rotation * math.Pi / 180)
+
+	// step 1: compute (x1', y1')
+	dx, dy := (from.X-to.X)/2, (from.Y-to.Y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+
+	// F.6.6: ensure radii are large enough
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+
+	// step 2: compute (cx', cy')
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cx1, cy1 := coef*rx*y1/ry, -coef*ry*x1/rx
+
+	// step 3: compute (cx, cy)
+	center := Point{
+		cos*cx1 - sin*cy1 + (from.X+to.X)/2,
+		sin*cx1 + cos*cy1 + (from.Y+to.Y)/2,
+	}
+
+	// step 4: compute start angle and sweep
+	ux, uy := (x1-cx1)/rx, (y1-cy1)/ry
+	vx, vy := (-x1-cx1)/rx, (-y1-cy1)/ry
+	start := vectorAngle(1, 0, ux, uy)
+	delta := math.Mod(vectorAngle(ux, uy, vx, vy), 2*math.Pi)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	return Arc{center, rx, ry, rotation, start * 180 / math.Pi, delta * 180 / math.Pi}, true
+}
+
+// CenterToEndpoint converts the arc to the endpoint parameterization used by
+// the 'A' command, following the SVG Implementation Notes F.6.4.
+func (a Arc) CenterToEndpoint() (from Point, rx, ry, rotation float64, largeArc, sweep bool, to Point) {
+	return a.PointAt(0), a.RX, a.RY, a.Rotation, math.Abs(a.Sweep) > 180, a.Sweep > 0, a.PointAt(1)
+}
+
+// angle returns the angle in radians at parameter t in [0, 1].
+func (a Arc) angle(t float64) float64 {
+	return (a.Start + t*a.Sweep) * math.Pi / 180
+}
+
+// PointAt returns the point at parameter t in [0, 1] along the arc.
+func (a Arc) PointAt(t float64) Point {
+	sinPhi, cosPhi := math.Sincos(a.Rotation * math.Pi / 180)
+	sin, cos := math.Sincos(a.angle(t))
+	return Point{
+		a.Center.X + a.RX*cos*cosPhi - a.RY*sin*sinPhi,
+		a.Center.Y + a.RX*cos*sinPhi + a.RY*sin*cosPhi,
+	}
+}
+
+// Derivative returns the derivative of the arc with respect to t at
+// parameter t in [0, 1].
+func (a Arc) Derivative(t float64) Point {
+	sinPhi, cosPhi := math.Sincos(a.Rotation * math.Pi / 180)
+	sin, cos := math.Sincos(a.angle(t))
+	d := a.Sweep * math.Pi / 180
+	return Point{
+		d * (-a.RX*sin*cosPhi - a.RY*cos*sinPhi),
+		d * (-a.RX*sin*sinPhi + a.RY*cos*cosPhi),
+	}
+}
+
+// arcError returns the maximum radial deviation of a cubic approximating a
+// circular arc of unit radius with the given sweep in radians.
+func arcError(sweep float64) float64 {
+	sin, cos := math.Sincos(math.Abs(sweep) / 4)
+	return 2.0 / 27 * math.Pow(sin, 6) / (cos * cos)
+}
+
+// Cubics approximates the arc with cubic Bézier curves which deviate at most
+// tolerance from the exact arc. The result consists of absolute 'C' commands
+// starting at the start point of the arc.
+func (a Arc) Cubics(tolerance float64) []*Command {
+	sweep := a.Sweep * math.Pi / 180
+	if sweep == 0 {
+		return nil
+	}
+	radius := math.Max(a.RX, a.RY)
+	n := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2)))
+	if n < 1 {
+		n = 1
+	}
+	for tolerance > 0 && n < 1024 && radius*arcError(sweep/float64(n)) > tolerance {
+		n++
+	}
+
+	// handle length for a segment of the given sweep
+	k := 4.0 / 3 * math.Tan(sweep/float64(n)/4)
+	commands := make([]*Command, 0, n)
+	for i := 0; i < n; i++ {
+		t0, t1 := float64(i)/float64(n), float64(i+1)/float64(n)
+		p0, p3 := a.PointAt(t0), a.PointAt(t1)
+		d0, d3 := a.Derivative(t0), a.Derivative(t1)
+		// derivatives are for the whole sweep; scale to a unit angle
+		s := k / sweep
+		commands = append(commands, &Command{"C", []float64{
+			p0.X + d0.X*s, p0.Y + d0.Y*s,
+			p3.X - d3.X*s, p3.Y - d3.Y*s,
+			p3.X, p3.Y,
+		}})
+	}
+	return commands
+}
+
+// ArcsToCubics returns a normalized copy of the path in which every arc is
+// approximated by cubic Bézier curves within the given tolerance.
+func (p *Path) ArcsToCubics(tolerance float64) *Path {
+	path := &Path{}
+	var cur Point
+	for _, subpath := range p.Normalize().Subpaths {
+		s := &Subpath{}
+		for _, c := range subpath.Commands {
+			if c.Symbol != "A" {
+				s.Commands = append(s.Commands, c)
+			} else {
+				to := Point{c.Params[5], c.Params[6]}
+				arc, ok := EndpointToCenter(cur, c.Params[0], c.Params[1], c.Params[2], c.Params[3] != 0, c.Params[4] != 0, to)
+				switch {
+				case ok:
+					cubics := arc.Cubics(tolerance)
+					// end exactly where the arc ends despite rounding
+					last := cubics[len(cubics)-1].Params
+					last[4], last[5] = to.X, to.Y
+					s.Commands = append(s.Commands, cubics...)
+				case cur != to:
+					s.Commands = append(s.Commands, &Command{"L", []float64{to.X, to.Y}})
+				}
+			}
+			if c.Symbol != "Z" {
+				cur = Point{c.Params[len(c.Params)-2], c.Params[len(c.Params)-1]}
+			} else {
+				cur = Point{s.Commands[0].Params[0], s.Commands[0].Params[1]}
+			}
+		}
+		path.Subpaths = append(path.Subpaths, s)
+	}
+	return path
+}
@@ -0,0 +1,78 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestEndpointToCenter(t *testing.T) {
+	var testCases = []struct {
+		from, to        utils.Point
+		rx, ry          float64
+		largeArc, sweep bool
+		expected        utils.Arc
+	}{
+		{utils.Point{0, 0}, utils.Point{20, 0}, 10, 10, false, true, utils.Arc{utils.Point{10, 0}, 10, 10, 0, 180, 180}},
+		{utils.Point{0, 0}, utils.Point{20, 0}, 10, 10, false, false, utils.Arc{utils.Point{10, 0}, 10, 10, 0, 180, -180}},
+		// radii too small are scaled up
+		{utils.Point{0, 0}, utils.Point{20, 0}, 5, 5, false, true, utils.Arc{utils.Point{10, 0}, 10, 10, 0, 180, 180}},
+		{utils.Point{10, 0}, utils.Point{0, 10}, 10, 10, false, true, utils.Arc{utils.Point{0, 0}, 10, 10, 0, 0, 90}},
+		{utils.Point{10, 0}, utils.Point{0, 10}, 10, 10, true, false, utils.Arc{utils.Point{0, 0}, 10, 10, 0, 0, -270}},
+		{utils.Point{10, 0}, utils.Point{0, 10}, 10, 10, true, true, utils.Arc{utils.Point{10, 10}, 10, 10, 0, -90, 270}},
+	}
+
+	for _, test := range testCases {
+		arc, ok := utils.EndpointToCenter(test.from, test.rx, test.ry, 0, test.largeArc, test.sweep, test.to)
+		e := test.expected
+		if !ok || math.Abs(arc.Center.X-e.Center.X) > 1e-9 || math.Abs(arc.Center.Y-e.Center.Y) > 1e-9 ||
+			math.Abs(arc.RX-e.RX) > 1e-9 || math.Abs(arc.RY-e.RY) > 1e-9 ||
+			math.Abs(math.Mod(arc.Start-e.Start, 360)) > 1e-9 || math.Abs(arc.Sweep-e.Sweep) > 1e-9 {
+			t.Errorf("EndpointToCenter: expected %+v, actual %+v\n", e, arc)
+		}
+		if p := arc.PointAt(1); math.Abs(p.X-test.to.X) > 1e-9 || math.Abs(p.Y-test.to.Y) > 1e-9 {
+			t.Errorf("PointAt: expected %v, actual %v\n", test.to, p)
+		}
+	}
+
+	if _, ok := utils.EndpointToCenter(utils.Point{0, 0}, 0, 10, 0, false, false, utils.Point{10, 0}); ok {
+		t.Error("EndpointToCenter: expected straight line for zero radius")
+	}
+}
+
+func TestArcCubics(t *testing.T) {
+	arc := utils.Arc{utils.Point{5, 5}, 10, 10, 0, 30, 300}
+	for _, tolerance := range []float64{1, 0.01, 0.0001} {
+		cubics := arc.Cubics(tolerance)
+		start := arc.PointAt(0)
+		for _, c := range cubics {
+			p := c.Params
+			for i := 0; i <= 10; i++ {
+				t0 := float64(i) / 10
+				mt := 1 - t0
+				x := mt*mt*mt*start.X + 3*mt*mt*t0*p[0] + 3*mt*t0*t0*p[2] + t0*t0*t0*p[4]
+				y := mt*mt*mt*start.Y + 3*mt*mt*t0*p[1] + 3*mt*t0*t0*p[3] + t0*t0*t0*p[5]
+				if d := math.Abs(math.Hypot(x-5, y-5) - 10); d > tolerance {
+					t.Errorf("Cubics: deviation %v exceeds tolerance %v\n", d, tolerance)
+				}
+			}
+			start = utils.Point{p[4], p[5]}
+		}
+		if end := arc.PointAt(1); math.Abs(start.X-end.X) > 1e-9 || math.Abs(start.Y-end.Y) > 1e-9 {
+			t.Errorf("Cubics: expected end %v, actual %v\n", end, start)
+		}
+	}
+}
+
+func TestArcsToCubics(t *testing.T) {
+	path, _ := utils.PathParser("M0 0 a10 10 0 0 1 20 0 A0 5 0 0 1 30 0 Z")
+	actual := path.ArcsToCubics(0.01)
+	symbols := ""
+	for _, c := range actual.Subpaths[0].Commands {
+		symbols += c.Symbol
+	}
+	if symbols != "MCCLZ" {
+		t.Errorf("ArcsToCubics: expected %v, actual %v\n", "MCCLZ", symbols)
+	}
+}
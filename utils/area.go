@@ -0,0 +1,141 @@
+package utils
+
+import "math"
+
+// integrate integrates f over the segment, where f receives the point and the
+// derivative at a parameter. The 5-point Gauss-Legendre quadrature is exact
+// for the polynomials of lines and Bézier curves; arcs are split into pieces
+// of at most 15 degrees, on which it is exact to machine precision.
+func (s Segment) integrate(f func(p, d Point) float64) float64 {
+	n := 1
+	if s.Symbol == "A" {
+		n = int(math.Ceil(math.Abs(s.Arc.Sweep) / 15))
+	}
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		a, b := float64(i)/float64(n), float64(i+1)/float64(n)
+		half, mid := (b-a)/2, (a+b)/2
+		for j, x := range gaussNodes {
+			t := mid + half*x
+			sum += gaussWeights[j] * f(s.PointAt(t), s.Derivative(t)) * half
+		}
+	}
+	return sum
+}
+
+// closedSegments returns the segments of the contour closed with a line, as
+// when it is filled.
+func (c Contour) closedSegments() []Segment {
+	if end := c.End(); end != c.Start {
+		return append(append([]Segment{}, c.Segments...), Segment{"L", []Point{end, c.Start}, Arc{}})
+	}
+	return c.Segments
+}
+
+// Area returns the signed area enclosed by the contour, which is positive if
+// the contour runs clockwise as displayed, i.e. with the y axis pointing
+// down, and negative if it runs counter-clockwise. Open contours are closed
+// with a line. Curves and arcs are integrated exactly rather than flattened.
+func (c Contour) Area() float64 {
+	area := 0.0
+	for _, s := range c.closedSegments() {
+		area += s.integrate(func(p, d Point) float64 { return p.X*d.Y - p.Y*d.X })
+	}
+	return area / 2
+}
+
+// Centroid returns the centroid of the area enclosed by the contour. It
+// returns the start point for contours without area.
+func (c Contour) Centroid() Point {
+	area := c.Area()
+	if area == 0 {
+		return c.Start
+	}
+	var x, y float64
+	for _, s := range c.closedSegments() {
+		x += s.integrate(func(p, d Point) float64 { return p.X * p.X * d.Y })
+		y -= s.integrate(func(p, d Point) float64 { return p.Y * p.Y * d.X })
+	}
+	return Point{x / (2 * area), y / (2 * area)}
+}
+
+// IsClockwise returns true if the contour runs clockwise as displayed.
+func (c Contour) IsClockwise() bool {
+	return c.Area() > 0
+}
+
+// winding returns the winding number of the closed contour around p.
+func (c Contour) winding(p Point) int {
+	w := 0
+	for _, s := range c.closedSegments() {
+		w += s.winding(p)
+	}
+	return w
+}
+
+// Area returns the signed area enclosed by the subpath on its own, positive
+// for clockwise subpaths. See Contour.Area.
+func (s *Subpath) Area() float64 {
+	return s.contour().Area()
+}
+
+// Centroid returns the centroid of the area enclosed by the subpath.
+func (s *Subpath) Centroid() Point {
+	return s.contour().Centroid()
+}
+
+// IsClockwise returns true if the subpath runs clockwise as displayed.
+func (s *Subpath) IsClockwise() bool {
+	return s.contour().IsClockwise()
+}
+
+// Area returns the sum of the signed areas of the subpaths.
+func (p *Path) Area() float64 {
+	area := 0.0
+	for _, c := range p.Contours() {
+		area += c.Area()
+	}
+	return area
+}
+
+// Centroid returns the centroid of the area enclosed by the path, where
+// subpaths count with the sign of their area.
+func (p *Path) Centroid() Point {
+	var x, y, area float64
+	for _, c := range p.Contours() {
+		a := c.Area()
+		centroid := c.Centroid()
+		x, y, area = x+centroid.X*a, y+centroid.Y*a, area+a
+	}
+	if area == 0 {
+		return Point{}
+	}
+	return Point{x / area, y / area}
+}
+
+// Orient returns a copy of the path in absolute commands in which outer
+// contours run clockwise and holes counter-clockwise, or the reverse if
+// outerClockwise is false. A contour is a hole if it lies inside an odd
+// number of other contours. Contours without area are left as they are.
+func (p *Path) Orient(outerClockwise bool) *Path {
+	contours := p.Contours()
+	oriented := make([]Contour, len(contours))
+	for i, c := range contours {
+		oriented[i] = c
+		area := c.Area()
+		if area == 0 {
+			continue
+		}
+		depth := 0
+		for j, o := range contours {
+			if i != j && o.Area() != 0 && o.winding(c.Start) != 0 && math.Abs(o.Area()) > math.Abs(area) {
+				depth++
+			}
+		}
+		clockwise := (depth%2 == 0) == outerClockwise
+		if (area > 0) != clockwise {
+			oriented[i] = c.Reverse()
+		}
+	}
+	return PathFromContours(oriented)
+}
@@ -0,0 +1,76 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestSubpathArea(t *testing.T) {
+	testCases := []struct {
+		path      string
+		area      float64
+		centroid  utils.Point
+		clockwise bool
+	}{
+		{"M0 0 H10 V10 H0 Z", 100, utils.Point{5, 5}, true},
+		{"M0 0 V10 H10 V0 Z", -100, utils.Point{5, 5}, false},
+		// open subpaths are closed with a line
+		{"M0 0 H10 V10", 50, utils.Point{20.0 / 3, 10.0 / 3}, true},
+		{"M0 0 H10 V10 Z", 50, utils.Point{20.0 / 3, 10.0 / 3}, true},
+		{"M0 10 A10 10 0 0 1 20 10 A10 10 0 0 1 0 10 Z", 100 * math.Pi, utils.Point{10, 10}, true},
+		// a half disk above its diameter
+		{"M0 10 A10 10 0 0 1 20 10 Z", 50 * math.Pi, utils.Point{10, 10 - 40/(3*math.Pi)}, true},
+		{"M5 0 A10 5 90 0 0 5 20 Z", -math.Pi * 50 / 2, utils.Point{5 - 20/(3*math.Pi), 10}, false},
+		// the area under a parabola is two thirds of its bounding box
+		{"M0 0 Q5 10 10 0 Z", -100.0 / 3 * 2 / 2, utils.Point{5, 2}, false},
+		{"M0 0 C0 10 10 10 10 0 Z", -60, utils.Point{5, 45.0 / 14}, false},
+		{"M0 0 L10 0", 0, utils.Point{0, 0}, false},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.path)
+		subpath := path.Subpaths[0]
+		if area := subpath.Area(); !closeTo(area, test.area, 1e-9) {
+			t.Errorf("Area %q: expected %v, actual %v\n", test.path, test.area, area)
+		}
+		if test.centroid != (utils.Point{}) {
+			if c := subpath.Centroid(); !closeTo(c.X, test.centroid.X, 1e-9) || !closeTo(c.Y, test.centroid.Y, 1e-9) {
+				t.Errorf("Centroid %q: expected %v, actual %v\n", test.path, test.centroid, c)
+			}
+		}
+		if cw := subpath.IsClockwise(); cw != test.clockwise {
+			t.Errorf("IsClockwise %q: expected %v, actual %v\n", test.path, test.clockwise, cw)
+		}
+	}
+}
+
+func TestPathOrient(t *testing.T) {
+	// an outer square and a hole, both counter-clockwise, and a clockwise
+	// island inside the hole
+	path, _ := utils.PathParser("M0 0 V30 H30 V0 Z M10 10 V20 H20 V10 Z M13 13 H17 V17 H13 Z")
+	testCases := []struct {
+		outerClockwise bool
+		expected       []bool
+	}{
+		{true, []bool{true, false, true}},
+		{false, []bool{false, true, false}},
+	}
+
+	for _, test := range testCases {
+		oriented := path.Orient(test.outerClockwise)
+		for i, s := range oriented.Subpaths {
+			if cw := s.IsClockwise(); cw != test.expected[i] {
+				t.Errorf("Orient %v: subpath %v expected clockwise %v, actual %v\n", test.outerClockwise, i, test.expected[i], cw)
+			}
+		}
+		if area := oriented.Area(); !closeTo(math.Abs(area), 900-100+16, 1e-9) {
+			t.Errorf("Orient %v: expected area %v, actual %v\n", test.outerClockwise, 816, area)
+		}
+	}
+
+	if c := path.Orient(true).Centroid(); !closeTo(c.X, 15, 1e-9) || !closeTo(c.Y, 15, 1e-9) {
+		t.Errorf("Centroid: expected %v, actual %v\n", utils.Point{15, 15}, c)
+	}
+}
@@ -0,0 +1,383 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// FillRule determines which points are inside a path.
+type FillRule int
+
+const (
+	// NonZero treats points with a non-zero winding number as inside.
+	NonZero FillRule = iota
+	// EvenOdd treats points with an odd winding number as inside.
+	EvenOdd
+)
+
+// ParseFillRule returns the fill rule of a 'fill-rule' or 'clip-rule'
+// value. Unknown values give the initial value nonzero.
+func ParseFillRule(value string) FillRule {
+	if value == "evenodd" {
+		return EvenOdd
+	}
+	return NonZero
+}
+
+// inside returns true if the winding number is inside according to the rule.
+func (r FillRule) inside(winding int) bool {
+	if r == EvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
+
+// BooleanOperation is a boolean operation on the areas of two paths.
+type BooleanOperation int
+
+const (
+	// Union is the area of either path.
+	Union BooleanOperation = iota
+	// Intersection is the area of both paths.
+	Intersection
+	// Difference is the area of the first path which is not in the second.
+	Difference
+	// Xor is the area of exactly one of the paths.
+	Xor
+)
+
+func (op BooleanOperation) apply(a, b bool) bool {
+	switch op {
+	case Intersection:
+		return a && b
+	case Difference:
+		return a && !b
+	case Xor:
+		return a != b
+	}
+	return a || b
+}
+
+// BooleanOptions controls boolean operations on paths.
+type BooleanOptions struct {
+	// Tolerance is the maximum deviation of the flattened curves. Zero uses
+	// a default of 0.1.
+	Tolerance float64
+	// FillRuleA and FillRuleB are the fill rules of the operands.
+	FillRuleA, FillRuleB FillRule
+	// RefitCurves fits cubic curves to the resulting polygons.
+	RefitCurves bool
+}
+
+// edge is a directed line between two vertices of one of the operands.
+type edge struct {
+	from, to Point
+	operand  int
+}
+
+// edgeKey identifies an undirected edge by its ordered end points.
+type edgeKey struct {
+	a, b Point
+}
+
+func (e edge) key() edgeKey {
+	if e.to.X < e.from.X || e.to.X == e.from.X && e.to.Y < e.from.Y {
+		return edgeKey{e.to, e.from}
+	}
+	return edgeKey{e.from, e.to}
+}
+
+// snapper rounds points to a grid, so that intersections computed from
+// different edges meet exactly.
+type snapper float64
+
+func (s snapper) point(p Point) Point {
+	return Point{math.Round(p.X/float64(s)) * float64(s), math.Round(p.Y/float64(s)) * float64(s)}
+}
+
+// polygonEdges appends the edges of the flattened path, treating every
+// subpath as closed like filling does.
+func polygonEdges(edges []edge, p *Path, operand int, tolerance float64, snap snapper) []edge {
+	for _, polyline := range p.Flatten(tolerance) {
+		points := polyline.Points
+		for i := range points {
+			from, to := snap.point(points[i]), snap.point(points[(i+1)%len(points)])
+			if from != to {
+				edges = append(edges, edge{from, to, operand})
+			}
+		}
+	}
+	return edges
+}
+
+// projection returns the parameter of the point on e nearest to p.
+func projection(p Point, e edge) float64 {
+	d := sub(e.to, e.from)
+	return math.Max(0, math.Min(1, dot(sub(p, e.from), d)/dot(d, d)))
+}
+
+// intersectEdges returns the parameters on e and o of their intersections,
+// including the end points of either lying on the other.
+func intersectEdges(e, o edge, snap float64) ([]float64, []float64) {
+	var s, t []float64
+	d1, d2 := sub(e.to, e.from), sub(o.to, o.from)
+	den := d1.X*d2.Y - d1.Y*d2.X
+	if math.Abs(den) > 1e-12*math.Hypot(d1.X, d1.Y)*math.Hypot(d2.X, d2.Y) {
+		w := sub(o.from, e.from)
+		a := (w.X*d2.Y - w.Y*d2.X) / den
+		b := (w.X*d1.Y - w.Y*d1.X) / den
+		if a >= 0 && a <= 1 && b >= 0 && b <= 1 {
+			s, t = append(s, a), append(t, b)
+		}
+	}
+	// end points touching the other edge, which covers collinear overlaps
+	for _, p := range []Point{o.from, o.to} {
+		if distanceToSegment(p, e.from, e.to) <= snap {
+			s = append(s, projection(p, e))
+		}
+	}
+	for _, p := range []Point{e.from, e.to} {
+		if distanceToSegment(p, o.from, o.to) <= snap {
+			t = append(t, projection(p, o))
+		}
+	}
+	return s, t
+}
+
+// splitEdges splits every edge at its intersections with all other edges, so
+// that edges only meet at their end points.
+func splitEdges(edges []edge, snap snapper) []edge {
+	minX := func(e edge) float64 { return math.Min(e.from.X, e.to.X) }
+	maxX := func(e edge) float64 { return math.Max(e.from.X, e.to.X) }
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return minX(edges[order[i]]) < minX(edges[order[j]]) })
+
+	params := make([][]float64, len(edges))
+	for oi, i := range order {
+		e := edges[i]
+		for _, j := range order[oi+1:] {
+			o := edges[j]
+			if minX(o) > maxX(e)+float64(snap) {
+				break
+			}
+			if math.Min(o.from.Y, o.to.Y) > math.Max(e.from.Y, e.to.Y)+float64(snap) ||
+				math.Max(o.from.Y, o.to.Y) < math.Min(e.from.Y, e.to.Y)-float64(snap) {
+				continue
+			}
+			s, t := intersectEdges(e, o, float64(snap))
+			params[i] = append(params[i], s...)
+			params[j] = append(params[j], t...)
+		}
+	}
+
+	var split []edge
+	for i, e := range edges {
+		ts := append(params[i], 1)
+		sort.Float64s(ts)
+		previous := e.from
+		for _, t := range ts {
+			p := snap.point(lerp(e.from, e.to, t))
+			if t == 1 {
+				p = e.to
+			}
+			if p != previous {
+				split = append(split, edge{previous, p, e.operand})
+				previous = p
+			}
+		}
+	}
+	return split
+}
+
+// winding returns the winding number of the edges of the operand at a point
+// just beyond p along the x axis, or the y axis when transpose is set. The
+// sign of the result depends on the axis. Edges with the key skip are
+// ignored.
+func winding(edges []edge, operand int, p Point, transpose bool, skip edgeKey) int {
+	if transpose {
+		p = Point{p.Y, p.X}
+	}
+	w := 0
+	for _, e := range edges {
+		if e.operand != operand || e.key() == skip {
+			continue
+		}
+		a, b := e.from, e.to
+		if transpose {
+			a, b = Point{a.Y, a.X}, Point{b.Y, b.X}
+		}
+		if (a.Y <= p.Y) == (b.Y <= p.Y) {
+			continue
+		}
+		if a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) > p.X {
+			if b.Y > a.Y {
+				w++
+			} else {
+				w--
+			}
+		}
+	}
+	return w
+}
+
+// sides returns the winding numbers of an operand on the left and the right
+// of the undirected edge k, where left is the side of the normal (-dy, dx).
+// count is the net number of edges of the operand running from k.a to k.b.
+func sides(edges []edge, operand int, k edgeKey, count int) (int, int) {
+	d := sub(k.b, k.a)
+	// cast the ray along the axis the edge is least parallel to
+	transpose := math.Abs(d.Y) < math.Abs(d.X)
+	beyond := winding(edges, operand, lerp(k.a, k.b, 0.5), transpose, k)
+	along, normal := d.Y, -d.Y
+	if transpose {
+		along, normal = d.X, d.X
+	}
+	// crossing the edge itself against the ray changes the winding number
+	before := beyond
+	if along > 0 {
+		before += count
+	} else {
+		before -= count
+	}
+	if normal > 0 {
+		return beyond, before
+	}
+	return before, beyond
+}
+
+// turn returns the signed angle from direction in to direction out.
+func turn(in, out Point) float64 {
+	return math.Atan2(in.X*out.Y-in.Y*out.X, dot(in, out))
+}
+
+// traceLoops links directed edges into closed loops, turning towards the
+// left at vertices with several choices so that loops touching at a vertex
+// stay separate.
+func traceLoops(edges []edge) [][]Point {
+	outgoing := map[Point][]int{}
+	for i, e := range edges {
+		outgoing[e.from] = append(outgoing[e.from], i)
+	}
+	used := make([]bool, len(edges))
+	var loops [][]Point
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		start, current := edges[i].from, edges[i]
+		loop := []Point{start}
+		for current.to != start {
+			loop = append(loop, current.to)
+			next, best := -1, math.Inf(-1)
+			for _, j := range outgoing[current.to] {
+				if a := turn(sub(current.to, current.from), sub(edges[j].to, edges[j].from)); !used[j] && a > best {
+					next, best = j, a
+				}
+			}
+			if next < 0 {
+				break
+			}
+			used[next] = true
+			current = edges[next]
+		}
+		if len(loop) >= 3 {
+			loops = append(loops, loop)
+		}
+	}
+	return loops
+}
+
+// Boolean combines the areas of two paths with a boolean operation. Curves
+// are flattened within the tolerance of the options and every subpath is
+// treated as closed. The result consists of closed polygons with outer
+// contours clockwise and holes counter-clockwise, so it fills the same with
+// either fill rule.
+func Boolean(a, b *Path, op BooleanOperation, opts BooleanOptions) *Path {
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = 0.1
+	}
+	snap := snapper(tolerance / 1000)
+
+	edges := polygonEdges(nil, a, 0, tolerance, snap)
+	edges = splitEdges(polygonEdges(edges, b, 1, tolerance, snap), snap)
+
+	counts := map[edgeKey]*[2]int{}
+	var keys []edgeKey
+	for _, e := range edges {
+		k := e.key()
+		if counts[k] == nil {
+			counts[k] = &[2]int{}
+			keys = append(keys, k)
+		}
+		if e.from == k.a {
+			counts[k][e.operand]++
+		} else {
+			counts[k][e.operand]--
+		}
+	}
+
+	rules := [2]FillRule{opts.FillRuleA, opts.FillRuleB}
+	var boundary []edge
+	for _, k := range keys {
+		var left, right [2]bool
+		for operand := range rules {
+			l, r := sides(edges, operand, k, counts[k][operand])
+			left[operand], right[operand] = rules[operand].inside(l), rules[operand].inside(r)
+		}
+		insideLeft, insideRight := op.apply(left[0], left[1]), op.apply(right[0], right[1])
+		// keep the inside of the result on the left
+		if insideLeft && !insideRight {
+			boundary = append(boundary, edge{k.a, k.b, 0})
+		} else if insideRight && !insideLeft {
+			boundary = append(boundary, edge{k.b, k.a, 0})
+		}
+	}
+
+	path := &Path{}
+	for _, loop := range traceLoops(boundary) {
+		points := SimplifyPolyline(append(loop, loop[0]), float64(snap))
+		points = points[:len(points)-1]
+		if len(points) < 3 {
+			continue
+		}
+		s := &Subpath{}
+		for i, p := range points {
+			symbol := "L"
+			if i == 0 {
+				symbol = "M"
+			}
+			s.Commands = append(s.Commands, &Command{symbol, []float64{p.X, p.Y}})
+		}
+		s.Commands = append(s.Commands, &Command{"Z", []float64{}})
+		path.Subpaths = append(path.Subpaths, s)
+	}
+	if opts.RefitCurves {
+		path = path.FitCurves(tolerance)
+	}
+	return path
+}
+
+// Union returns the area of either path.
+func (p *Path) Union(o *Path, opts BooleanOptions) *Path {
+	return Boolean(p, o, Union, opts)
+}
+
+// Intersection returns the area of both paths.
+func (p *Path) Intersection(o *Path, opts BooleanOptions) *Path {
+	return Boolean(p, o, Intersection, opts)
+}
+
+// Difference returns the area of the path which is not in the other path.
+func (p *Path) Difference(o *Path, opts BooleanOptions) *Path {
+	return Boolean(p, o, Difference, opts)
+}
+
+// Xor returns the area of exactly one of the paths.
+func (p *Path) Xor(o *Path, opts BooleanOptions) *Path {
+	return Boolean(p, o, Xor, opts)
+}
@@ -0,0 +1,98 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// polygonArea returns the area enclosed by the polygons of the path counted
+// with the sign of their orientation.
+func polygonArea(p *utils.Path) float64 {
+	area := 0.0
+	for _, polyline := range p.Flatten(0.01) {
+		points := polyline.Points
+		for i := range points {
+			a, b := points[i], points[(i+1)%len(points)]
+			area += (a.X*b.Y - b.X*a.Y) / 2
+		}
+	}
+	return area
+}
+
+func TestBoolean(t *testing.T) {
+	square := "M0 0 H10 V10 H0 Z"
+	shifted := "M5 5 H15 V15 H5 Z"
+	testCases := []struct {
+		a, b     string
+		op       utils.BooleanOperation
+		rules    [2]utils.FillRule
+		area     float64
+		subpaths int
+	}{
+		{square, shifted, utils.Union, [2]utils.FillRule{}, 175, 1},
+		{square, shifted, utils.Intersection, [2]utils.FillRule{}, 25, 1},
+		{square, shifted, utils.Difference, [2]utils.FillRule{}, 75, 1},
+		{square, shifted, utils.Xor, [2]utils.FillRule{}, 150, 2},
+		{square, "M20 0 H30 V10 H20 Z", utils.Union, [2]utils.FillRule{}, 200, 2},
+		{square, "M20 0 H30 V10 H20 Z", utils.Intersection, [2]utils.FillRule{}, 0, 0},
+		// shared edges
+		{square, "M10 0 H20 V10 H10 Z", utils.Union, [2]utils.FillRule{}, 200, 1},
+		{square, square, utils.Intersection, [2]utils.FillRule{}, 100, 1},
+		{square, square, utils.Xor, [2]utils.FillRule{}, 0, 0},
+		// a hole cut out of the square
+		{square, "M3 3 H7 V7 H3 Z", utils.Difference, [2]utils.FillRule{}, 84, 2},
+		// fill rules of nested squares in the same direction
+		{"M0 0 H10 V10 H0 Z M3 3 H7 V7 H3 Z", "M20 0 H21 V1 H20 Z", utils.Union, [2]utils.FillRule{utils.NonZero, utils.NonZero}, 101, 2},
+		{"M0 0 H10 V10 H0 Z M3 3 H7 V7 H3 Z", "M20 0 H21 V1 H20 Z", utils.Union, [2]utils.FillRule{utils.EvenOdd, utils.NonZero}, 85, 3},
+		// a self-intersecting bow tie
+		{"M0 0 L10 10 L10 0 L0 10 Z", square, utils.Intersection, [2]utils.FillRule{}, 50, 2},
+	}
+
+	for _, test := range testCases {
+		a, _ := utils.PathParser(test.a)
+		b, _ := utils.PathParser(test.b)
+		opts := utils.BooleanOptions{FillRuleA: test.rules[0], FillRuleB: test.rules[1]}
+		result := utils.Boolean(a, b, test.op, opts)
+		if area := polygonArea(result); math.Abs(area-test.area) > 1e-6 {
+			t.Errorf("Boolean %q %v %q: expected area %v, actual %v (%v)\n", test.a, test.op, test.b, test.area, area, result)
+		}
+		if n := len(result.Subpaths); n != test.subpaths {
+			t.Errorf("Boolean %q %v %q: expected %v subpaths, actual %v (%v)\n", test.a, test.op, test.b, test.subpaths, n, result)
+		}
+	}
+}
+
+func TestBooleanCurves(t *testing.T) {
+	circle, _ := utils.PathParser("M0 10 A10 10 0 0 1 20 10 A10 10 0 0 1 0 10 Z")
+	other, _ := utils.PathParser("M10 10 A10 10 0 0 1 30 10 A10 10 0 0 1 10 10 Z")
+	opts := utils.BooleanOptions{Tolerance: 0.01}
+
+	union := circle.Union(other, opts)
+	// two circles of radius 10 whose centers are 10 apart
+	lens := 2*100*math.Acos(0.5) - 5*math.Sqrt(300)
+	expected := 2*math.Pi*100 - lens
+	if area := polygonArea(union); math.Abs(area-expected) > 1 {
+		t.Errorf("Union: expected area %v, actual %v\n", expected, area)
+	}
+	if area := polygonArea(circle.Intersection(other, opts)); math.Abs(area-lens) > 1 {
+		t.Errorf("Intersection: expected area %v, actual %v\n", lens, area)
+	}
+
+	opts.RefitCurves = true
+	refit := circle.Union(other, opts)
+	if n := len(refit.Subpaths[0].Commands); n > 30 {
+		t.Errorf("RefitCurves: expected few commands, actual %v\n", n)
+	}
+	if area := polygonArea(refit); math.Abs(area-expected) > 1 {
+		t.Errorf("RefitCurves: expected area %v, actual %v\n", expected, area)
+	}
+}
+
+func TestParseFillRule(t *testing.T) {
+	if utils.ParseFillRule("evenodd") != utils.EvenOdd || utils.ParseFillRule("nonzero") != utils.NonZero ||
+		utils.ParseFillRule("inherit") != utils.NonZero {
+		t.Errorf("ParseFillRule: unexpected fill rule\n")
+	}
+}
@@ -0,0 +1,402 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSSTokenType is the type of a CSS token.
+type CSSTokenType int
+
+// Token types of CSS Syntax Level 3.
+const (
+	IdentToken CSSTokenType = iota
+	FunctionToken
+	AtKeywordToken
+	HashToken
+	StringToken
+	BadStringToken
+	URLToken
+	BadURLToken
+	DelimToken
+	NumberToken
+	PercentageToken
+	DimensionToken
+	WhitespaceToken
+	CDOToken
+	CDCToken
+	ColonToken
+	SemicolonToken
+	CommaToken
+	OpenSquareToken
+	CloseSquareToken
+	OpenParenToken
+	CloseParenToken
+	OpenCurlyToken
+	CloseCurlyToken
+)
+
+// CSSToken is a token of CSS source. Value holds the unescaped name of
+// identifiers, functions, at-keywords and hashes, the content of strings and
+// URLs and the character of delimiters. Numeric tokens carry their value in
+// Number and dimensions their unit in Unit. Raw is the source text of the
+// token, which serializes it unchanged.
+type CSSToken struct {
+	Type   CSSTokenType
+	Value  string
+	Number float64
+	Unit   string
+	Raw    string
+}
+
+const eof = rune(-1)
+
+// cssTokenizer splits preprocessed CSS source into tokens.
+type cssTokenizer struct {
+	input []rune
+	pos   int
+}
+
+// CSSTokenizer splits CSS source into tokens as specified by CSS Syntax
+// Level 3. Comments are dropped and malformed input gives bad string, bad
+// URL or delimiter tokens instead of errors, like browsers do.
+func CSSTokenizer(css string) []CSSToken {
+	// preprocessing replaces the newline variants and NUL
+	css = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\f", "\n", "\x00", "\uFFFD").Replace(css)
+	t := &cssTokenizer{input: []rune(css)}
+	var tokens []CSSToken
+	for {
+		t.consumeComments()
+		if t.peek(0) == eof {
+			return tokens
+		}
+		start := t.pos
+		token := t.consumeToken()
+		token.Raw = string(t.input[start:t.pos])
+		tokens = append(tokens, token)
+	}
+}
+
+func (t *cssTokenizer) peek(n int) rune {
+	if t.pos+n < len(t.input) {
+		return t.input[t.pos+n]
+	}
+	return eof
+}
+
+func (t *cssTokenizer) next() rune {
+	r := t.peek(0)
+	if r != eof {
+		t.pos++
+	}
+	return r
+}
+
+func (t *cssTokenizer) consumeComments() {
+	for t.peek(0) == '/' && t.peek(1) == '*' {
+		t.pos += 2
+		for t.peek(0) != eof && !(t.peek(0) == '*' && t.peek(1) == '/') {
+			t.pos++
+		}
+		if t.peek(0) != eof {
+			t.pos += 2
+		}
+	}
+}
+
+func isCSSWhitespace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
+
+func isCSSDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isHexDigit(r rune) bool {
+	return isCSSDigit(r) || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F'
+}
+
+func isNameStart(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r >= 0x80
+}
+
+func isName(r rune) bool {
+	return isNameStart(r) || isCSSDigit(r) || r == '-'
+}
+
+func isNonPrintable(r rune) bool {
+	return r >= 0 && r <= 8 || r == 0x0b || r >= 0x0e && r <= 0x1f || r == 0x7f
+}
+
+// validEscape returns true if the two code points start a valid escape.
+func validEscape(a, b rune) bool {
+	return a == '\\' && b != '\n'
+}
+
+// startsIdent returns true if the three code points would start an
+// identifier.
+func startsIdent(a, b, c rune) bool {
+	switch {
+	case a == '-':
+		return isNameStart(b) || b == '-' || validEscape(b, c)
+	case isNameStart(a):
+		return true
+	}
+	return validEscape(a, b)
+}
+
+// startsNumber returns true if the three code points would start a number.
+func startsNumber(a, b, c rune) bool {
+	switch a {
+	case '+', '-':
+		return isCSSDigit(b) || b == '.' && isCSSDigit(c)
+	case '.':
+		return isCSSDigit(b)
+	}
+	return isCSSDigit(a)
+}
+
+func (t *cssTokenizer) consumeToken() CSSToken {
+	c := t.next()
+	switch {
+	case isCSSWhitespace(c):
+		for isCSSWhitespace(t.peek(0)) {
+			t.pos++
+		}
+		return CSSToken{Type: WhitespaceToken, Value: " "}
+	case c == '"' || c == '\'':
+		return t.consumeString(c)
+	case c == '#':
+		if isName(t.peek(0)) || validEscape(t.peek(0), t.peek(1)) {
+			return CSSToken{Type: HashToken, Value: t.consumeName()}
+		}
+	case c == '(':
+		return CSSToken{Type: OpenParenToken, Value: "("}
+	case c == ')':
+		return CSSToken{Type: CloseParenToken, Value: ")"}
+	case c == '[':
+		return CSSToken{Type: OpenSquareToken, Value: "["}
+	case c == ']':
+		return CSSToken{Type: CloseSquareToken, Value: "]"}
+	case c == '{':
+		return CSSToken{Type: OpenCurlyToken, Value: "{"}
+	case c == '}':
+		return CSSToken{Type: CloseCurlyToken, Value: "}"}
+	case c == ',':
+		return CSSToken{Type: CommaToken, Value: ","}
+	case c == ':':
+		return CSSToken{Type: ColonToken, Value: ":"}
+	case c == ';':
+		return CSSToken{Type: SemicolonToken, Value: ";"}
+	case c == '+' || c == '.':
+		if startsNumber(c, t.peek(0), t.peek(1)) {
+			t.pos--
+			return t.consumeNumeric()
+		}
+	case c == '-':
+		if startsNumber(c, t.peek(0), t.peek(1)) {
+			t.pos--
+			return t.consumeNumeric()
+		}
+		if t.peek(0) == '-' && t.peek(1) == '>' {
+			t.pos += 2
+			return CSSToken{Type: CDCToken, Value: "-->"}
+		}
+		if startsIdent(c, t.peek(0), t.peek(1)) {
+			t.pos--
+			return t.consumeIdentLike()
+		}
+	case c == '<':
+		if t.peek(0) == '!' && t.peek(1) == '-' && t.peek(2) == '-' {
+			t.pos += 3
+			return CSSToken{Type: CDOToken, Value: "<!--"}
+		}
+	case c == '@':
+		if startsIdent(t.peek(0), t.peek(1), t.peek(2)) {
+			return CSSToken{Type: AtKeywordToken, Value: t.consumeName()}
+		}
+	case c == '\\':
+		if validEscape(c, t.peek(0)) {
+			t.pos--
+			return t.consumeIdentLike()
+		}
+	case isCSSDigit(c):
+		t.pos--
+		return t.consumeNumeric()
+	case isNameStart(c):
+		t.pos--
+		return t.consumeIdentLike()
+	}
+	return CSSToken{Type: DelimToken, Value: string(c)}
+}
+
+// consumeEscape consumes an escaped code point after the backslash.
+func (t *cssTokenizer) consumeEscape() rune {
+	c := t.next()
+	if c == eof {
+		return utf8.RuneError
+	}
+	if !isHexDigit(c) {
+		return c
+	}
+	hex := string(c)
+	for len(hex) < 6 && isHexDigit(t.peek(0)) {
+		hex += string(t.next())
+	}
+	if isCSSWhitespace(t.peek(0)) {
+		t.pos++
+	}
+	n, _ := strconv.ParseUint(hex, 16, 32)
+	if n == 0 || n >= 0xd800 && n <= 0xdfff || n > utf8.MaxRune {
+		return utf8.RuneError
+	}
+	return rune(n)
+}
+
+// consumeName consumes the code points of a name, resolving escapes.
+func (t *cssTokenizer) consumeName() string {
+	var b strings.Builder
+	for {
+		c := t.peek(0)
+		switch {
+		case isName(c):
+			b.WriteRune(t.next())
+		case validEscape(c, t.peek(1)):
+			t.pos++
+			b.WriteRune(t.consumeEscape())
+		default:
+			return b.String()
+		}
+	}
+}
+
+func (t *cssTokenizer) consumeString(quote rune) CSSToken {
+	var b strings.Builder
+	for {
+		c := t.peek(0)
+		switch {
+		case c == eof:
+			return CSSToken{Type: StringToken, Value: b.String()}
+		case c == quote:
+			t.pos++
+			return CSSToken{Type: StringToken, Value: b.String()}
+		case c == '\n':
+			return CSSToken{Type: BadStringToken}
+		case c == '\\':
+			t.pos++
+			switch t.peek(0) {
+			case eof:
+			case '\n':
+				t.pos++
+			default:
+				b.WriteRune(t.consumeEscape())
+			}
+		default:
+			b.WriteRune(t.next())
+		}
+	}
+}
+
+func (t *cssTokenizer) consumeNumeric() CSSToken {
+	start := t.pos
+	if t.peek(0) == '+' || t.peek(0) == '-' {
+		t.pos++
+	}
+	for isCSSDigit(t.peek(0)) {
+		t.pos++
+	}
+	if t.peek(0) == '.' && isCSSDigit(t.peek(1)) {
+		t.pos++
+		for isCSSDigit(t.peek(0)) {
+			t.pos++
+		}
+	}
+	if e := t.peek(0); e == 'e' || e == 'E' {
+		if isCSSDigit(t.peek(1)) || (t.peek(1) == '+' || t.peek(1) == '-') && isCSSDigit(t.peek(2)) {
+			t.pos += 2
+			for isCSSDigit(t.peek(0)) {
+				t.pos++
+			}
+		}
+	}
+	repr := string(t.input[start:t.pos])
+	n, _ := strconv.ParseFloat(repr, 64)
+
+	if startsIdent(t.peek(0), t.peek(1), t.peek(2)) {
+		return CSSToken{Type: DimensionToken, Value: repr, Number: n, Unit: t.consumeName()}
+	}
+	if t.peek(0) == '%' {
+		t.pos++
+		return CSSToken{Type: PercentageToken, Value: repr, Number: n}
+	}
+	return CSSToken{Type: NumberToken, Value: repr, Number: n}
+}
+
+func (t *cssTokenizer) consumeIdentLike() CSSToken {
+	name := t.consumeName()
+	if strings.EqualFold(name, "url") && t.peek(0) == '(' {
+		t.pos++
+		for isCSSWhitespace(t.peek(0)) && isCSSWhitespace(t.peek(1)) {
+			t.pos++
+		}
+		if q := t.peek(0); q == '"' || q == '\'' || isCSSWhitespace(q) && (t.peek(1) == '"' || t.peek(1) == '\'') {
+			return CSSToken{Type: FunctionToken, Value: name}
+		}
+		return t.consumeURL()
+	}
+	if t.peek(0) == '(' {
+		t.pos++
+		return CSSToken{Type: FunctionToken, Value: name}
+	}
+	return CSSToken{Type: IdentToken, Value: name}
+}
+
+func (t *cssTokenizer) consumeURL() CSSToken {
+	var b strings.Builder
+	for isCSSWhitespace(t.peek(0)) {
+		t.pos++
+	}
+	for {
+		c := t.next()
+		switch {
+		case c == ')' || c == eof:
+			return CSSToken{Type: URLToken, Value: b.String()}
+		case isCSSWhitespace(c):
+			for isCSSWhitespace(t.peek(0)) {
+				t.pos++
+			}
+			if t.peek(0) == ')' || t.peek(0) == eof {
+				t.next()
+				return CSSToken{Type: URLToken, Value: b.String()}
+			}
+			t.consumeBadURL()
+			return CSSToken{Type: BadURLToken}
+		case c == '"' || c == '\'' || c == '(' || isNonPrintable(c):
+			t.consumeBadURL()
+			return CSSToken{Type: BadURLToken}
+		case c == '\\':
+			if !validEscape(c, t.peek(0)) {
+				t.consumeBadURL()
+				return CSSToken{Type: BadURLToken}
+			}
+			b.WriteRune(t.consumeEscape())
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+// consumeBadURL consumes the remnants of a bad URL up to its closing
+// parenthesis.
+func (t *cssTokenizer) consumeBadURL() {
+	for {
+		c := t.next()
+		switch {
+		case c == ')' || c == eof:
+			return
+		case validEscape(c, t.peek(0)):
+			t.consumeEscape()
+		}
+	}
+}
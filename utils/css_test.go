@@ -0,0 +1,83 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestCSSTokenizer(t *testing.T) {
+	testCases := []struct {
+		css      string
+		expected []utils.CSSToken
+	}{
+		{
+			"fill:#fff/* c */;",
+			[]utils.CSSToken{
+				{Type: utils.IdentToken, Value: "fill", Raw: "fill"},
+				{Type: utils.ColonToken, Value: ":", Raw: ":"},
+				{Type: utils.HashToken, Value: "fff", Raw: "#fff"},
+				{Type: utils.SemicolonToken, Value: ";", Raw: ";"},
+			},
+		},
+		{
+			"-1.5e2px 50% +.5",
+			[]utils.CSSToken{
+				{Type: utils.DimensionToken, Value: "-1.5e2", Number: -150, Unit: "px", Raw: "-1.5e2px"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.PercentageToken, Value: "50", Number: 50, Raw: "50%"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.NumberToken, Value: "+.5", Number: 0.5, Raw: "+.5"},
+			},
+		},
+		{
+			`url( data:a,b ) url("x") 'a\'b'`,
+			[]utils.CSSToken{
+				{Type: utils.URLToken, Value: "data:a,b", Raw: "url( data:a,b )"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.FunctionToken, Value: "url", Raw: "url("},
+				{Type: utils.StringToken, Value: "x", Raw: `"x"`},
+				{Type: utils.CloseParenToken, Value: ")", Raw: ")"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.StringToken, Value: "a'b", Raw: `'a\'b'`},
+			},
+		},
+		{
+			`@media \31 0x --var <!-- -->`,
+			[]utils.CSSToken{
+				{Type: utils.AtKeywordToken, Value: "media", Raw: "@media"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.IdentToken, Value: "10x", Raw: `\31 0x`},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.IdentToken, Value: "--var", Raw: "--var"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.CDOToken, Value: "<!--", Raw: "<!--"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.CDCToken, Value: "-->", Raw: "-->"},
+			},
+		},
+		{
+			"\"a\nb url(a b)",
+			[]utils.CSSToken{
+				{Type: utils.BadStringToken, Raw: `"a`},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: "\n"},
+				{Type: utils.IdentToken, Value: "b", Raw: "b"},
+				{Type: utils.WhitespaceToken, Value: " ", Raw: " "},
+				{Type: utils.BadURLToken, Raw: "url(a b)"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		actual := utils.CSSTokenizer(test.css)
+		if len(actual) != len(test.expected) {
+			t.Errorf("CSSTokenizer %q: expected %v, actual %v\n", test.css, test.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("CSSTokenizer %q token %d: expected %+v, actual %+v\n", test.css, i, test.expected[i], actual[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package utils
+
+import "math"
+
+// Reverse returns the segment traversed in the opposite direction.
+func (s Segment) Reverse() Segment {
+	points := make([]Point, len(s.Points))
+	for i, p := range s.Points {
+		points[len(points)-1-i] = p
+	}
+	arc := s.Arc
+	if s.Symbol == "A" {
+		arc.Start, arc.Sweep = s.Arc.Start+s.Arc.Sweep, -s.Arc.Sweep
+	}
+	return Segment{s.Symbol, points, arc}
+}
+
+// Between returns the part of the segment between the parameters t0 and t1.
+func (s Segment) Between(t0, t1 float64) Segment {
+	if t0 > 0 {
+		_, s = s.Split(t0)
+		if t0 < 1 {
+			t1 = (t1 - t0) / (1 - t0)
+		}
+	}
+	if t1 < 1 {
+		s, _ = s.Split(t1)
+	}
+	return s
+}
+
+// End returns the end point of the contour.
+func (c Contour) End() Point {
+	if len(c.Segments) == 0 {
+		return c.Start
+	}
+	return c.Segments[len(c.Segments)-1].End()
+}
+
+// Reverse returns the contour traversed in the opposite direction. Closed
+// contours keep their start point and stay closed.
+func (c Contour) Reverse() Contour {
+	reversed := Contour{Start: c.End(), Closed: c.Closed}
+	for i := len(c.Segments) - 1; i >= 0; i-- {
+		reversed.Segments = append(reversed.Segments, c.Segments[i].Reverse())
+	}
+	return reversed
+}
+
+// Split splits the contour at parameter t, which ranges from 0 to the number
+// of segments. Its integer part selects the segment and its fraction the
+// parameter on that segment. Both parts are open contours.
+func (c Contour) Split(t float64) (Contour, Contour) {
+	n := len(c.Segments)
+	t = math.Max(0, math.Min(float64(n), t))
+	if n == 0 {
+		return Contour{Start: c.Start}, Contour{Start: c.Start}
+	}
+	i := int(t)
+	if i == n {
+		i--
+	}
+	local := t - float64(i)
+	a, b := c.Segments[i].Split(local)
+	first := Contour{Start: c.Start, Segments: append([]Segment{}, c.Segments[:i]...)}
+	if local > 0 {
+		first.Segments = append(first.Segments, a)
+	}
+	second := Contour{Start: b.Start(), Segments: append([]Segment{}, c.Segments[i+1:]...)}
+	if local < 1 {
+		second.Segments = append([]Segment{b}, second.Segments...)
+	}
+	return first, second
+}
+
+// Between returns the open contour between the distances l0 and l1 from the
+// start of the contour.
+func (c Contour) Between(l0, l1 float64) Contour {
+	var between Contour
+	started := false
+	acc := 0.0
+	for _, s := range c.Segments {
+		l := s.Length()
+		if acc+l >= l0 && acc <= l1 {
+			t0, t1 := s.ParameterAtLength(l0-acc), s.ParameterAtLength(l1-acc)
+			part := s.Between(t0, t1)
+			if !started {
+				between.Start, started = part.Start(), true
+			}
+			if t1 > t0 {
+				between.Segments = append(between.Segments, part)
+			}
+		}
+		acc += l
+	}
+	if !started {
+		between.Start = c.Start
+	}
+	return between
+}
+
+// Join returns the open contour which continues the contour with o,
+// connected by a line unless o starts where the contour ends.
+func (c Contour) Join(o Contour) Contour {
+	joined := Contour{Start: c.Start, Segments: append([]Segment{}, c.Segments...)}
+	if end := c.End(); end != o.Start {
+		joined.Segments = append(joined.Segments, Segment{"L", []Point{end, o.Start}, Arc{}})
+	}
+	joined.Segments = append(joined.Segments, o.Segments...)
+	return joined
+}
+
+// contour returns the contour of the subpath on its own. A subpath starting
+// with a relative moveto is taken relative to the origin.
+func (s *Subpath) contour() Contour {
+	contours := (&Path{Subpaths: []*Subpath{s}}).Contours()
+	if len(contours) == 0 {
+		return Contour{}
+	}
+	return contours[0]
+}
+
+// Reverse returns the subpath in absolute commands traversed in the opposite
+// direction. The shape of curves and the closed state are preserved.
+func (s *Subpath) Reverse() *Subpath {
+	return PathFromContours([]Contour{s.contour().Reverse()}).Subpaths[0]
+}
+
+// Reverse returns a copy of the path in absolute commands in which every
+// subpath is traversed in the opposite direction. The order of the subpaths
+// is kept.
+func (p *Path) Reverse() *Path {
+	contours := p.Contours()
+	for i, c := range contours {
+		contours[i] = c.Reverse()
+	}
+	return PathFromContours(contours)
+}
+
+// SplitAt splits the path at parameter t, which ranges from 0 to the number
+// of segments of all subpaths. Its integer part selects the segment and its
+// fraction the parameter on that segment. The split subpath becomes open.
+func (p *Path) SplitAt(t float64) (*Path, *Path) {
+	contours := p.Contours()
+	for i, c := range contours {
+		n := float64(len(c.Segments))
+		if t > n && i < len(contours)-1 {
+			t -= n
+			continue
+		}
+		a, b := c.Split(t)
+		first := append(append([]Contour{}, contours[:i]...), a)
+		second := append([]Contour{b}, contours[i+1:]...)
+		return PathFromContours(first), PathFromContours(second)
+	}
+	return &Path{}, &Path{}
+}
+
+// SplitAtLength splits the path at the given distance along it. The split
+// subpath becomes open.
+func (p *Path) SplitAtLength(length float64) (*Path, *Path) {
+	contours := p.Contours()
+	for i, c := range contours {
+		l := c.Length()
+		if length > l && i < len(contours)-1 {
+			length -= l
+			continue
+		}
+		first := append(append([]Contour{}, contours[:i]...), c.Between(0, length))
+		second := append([]Contour{c.Between(length, l)}, contours[i+1:]...)
+		return PathFromContours(first), PathFromContours(second)
+	}
+	return &Path{}, &Path{}
+}
+
+// Trim returns the part of the path between the fractions start and end of
+// its total length, as used for "draw on" animations. Subpaths which are
+// entirely within the range keep their closed state.
+func (p *Path) Trim(start, end float64) *Path {
+	contours := p.Contours()
+	total := 0.0
+	lengths := make([]float64, len(contours))
+	for i, c := range contours {
+		lengths[i] = c.Length()
+		total += lengths[i]
+	}
+	l0, l1 := math.Max(0, start)*total, math.Min(1, end)*total
+
+	var trimmed []Contour
+	acc := 0.0
+	for i, c := range contours {
+		from, to := acc, acc+lengths[i]
+		acc = to
+		if to < l0 || from > l1 || lengths[i] == 0 || l1 <= l0 {
+			continue
+		}
+		if from >= l0 && to <= l1 {
+			trimmed = append(trimmed, c)
+			continue
+		}
+		trimmed = append(trimmed, c.Between(l0-from, l1-from))
+	}
+	return PathFromContours(trimmed)
+}
+
+// Join returns a copy of the path in absolute commands in which all subpaths
+// are joined into a single open subpath. Subpaths are connected by lines
+// unless one starts where the previous one ends.
+func (p *Path) Join() *Path {
+	contours := p.Contours()
+	if len(contours) == 0 {
+		return &Path{}
+	}
+	joined := contours[0]
+	for _, c := range contours[1:] {
+		joined = joined.Join(c)
+	}
+	joined.Closed = false
+	return PathFromContours([]Contour{joined})
+}
+
+// absoluteSubpaths returns the subpaths of the path in absolute commands,
+// each starting with a moveto, so they do not depend on each other.
+func (p *Path) absoluteSubpaths() []*Subpath {
+	var subpaths []*Subpath
+	var cur, start Point
+	for _, subpath := range p.ToAbsolute().Subpaths {
+		s := &Subpath{}
+		for i, c := range subpath.Commands {
+			if i == 0 && c.Symbol != "M" {
+				s.Commands = append(s.Commands, &Command{"M", []float64{cur.X, cur.Y}})
+				start = cur
+			}
+			switch c.Symbol {
+			case "Z":
+				cur = start
+			case "H":
+				cur.X = c.Params[0]
+			case "V":
+				cur.Y = c.Params[0]
+			default:
+				cur = Point{c.Params[len(c.Params)-2], c.Params[len(c.Params)-1]}
+			}
+			if c.Symbol == "M" {
+				start = cur
+			}
+			s.Commands = append(s.Commands, c)
+		}
+		subpaths = append(subpaths, s)
+	}
+	return subpaths
+}
+
+// Reorder returns a copy of the path in absolute commands with the subpaths
+// at the given indices in the given order. Indices out of range are ignored.
+func (p *Path) Reorder(order []int) *Path {
+	subpaths := p.absoluteSubpaths()
+	path := &Path{}
+	for _, i := range order {
+		if i >= 0 && i < len(subpaths) {
+			path.Subpaths = append(path.Subpaths, subpaths[i])
+		}
+	}
+	return path
+}
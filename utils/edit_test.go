@@ -0,0 +1,107 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestPathReverse(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"M0 0 L10 0 L10 10", "M 10 10 L 10 0 L 0 0"},
+		{"M0 0 H10 V10 Z", "M 0 0 L 10 10 L 10 0 Z"},
+		{"M0 0 C0 10 10 10 10 0 Q15 -10 20 0", "M 20 0 Q 15 -10 10 0 C 10 10 0 10 0 0"},
+		{"M0 0 A10 10 0 1 0 10 10", "M 10 10 A 10 10 0 1 1 0 0"},
+		{"M0 0 L10 0 M20 0 l10 0", "M 10 0 L 0 0 M 30 0 L 20 0"},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.path)
+		reversed := path.Reverse()
+		if actual := reversed.String(); actual != test.expected {
+			t.Errorf("Reverse %q: expected %v, actual %v\n", test.path, test.expected, actual)
+		}
+		if a, b := reversed.Length(), path.Length(); math.Abs(a-b) > 1e-9 {
+			t.Errorf("Reverse %q: expected length %v, actual %v\n", test.path, b, a)
+		}
+		if actual := reversed.Reverse().String(); actual != path.Normalize().String() {
+			t.Errorf("Reverse twice %q: expected %v, actual %v\n", test.path, path.Normalize(), actual)
+		}
+	}
+
+	subpath, _ := utils.PathParser("M0 0 Q5 10 10 0 Z")
+	if actual := (&utils.Path{Subpaths: []*utils.Subpath{subpath.Subpaths[0].Reverse()}}).String(); actual != "M 0 0 L 10 0 Q 5 10 0 0 Z" {
+		t.Errorf("Subpath.Reverse: expected %v, actual %v\n", "M 0 0 L 10 0 Q 5 10 0 0 Z", actual)
+	}
+}
+
+func TestPathSplit(t *testing.T) {
+	path, _ := utils.PathParser("M0 0 L10 0 L10 10 M20 0 C20 10 30 10 30 0")
+	testCases := []struct {
+		t             float64
+		first, second string
+	}{
+		{0.5, "M 0 0 L 5 0", "M 5 0 L 10 0 L 10 10 M 20 0 C 20 10 30 10 30 0"},
+		{1, "M 0 0 L 10 0", "M 10 0 L 10 10 M 20 0 C 20 10 30 10 30 0"},
+		{2.5, "M 0 0 L 10 0 L 10 10 M 20 0 C 20 5 22.5 7.5 25 7.5", "M 25 7.5 C 27.5 7.5 30 5 30 0"},
+	}
+
+	for _, test := range testCases {
+		first, second := path.SplitAt(test.t)
+		if first.String() != test.first || second.String() != test.second {
+			t.Errorf("SplitAt %v: expected %v | %v, actual %v | %v\n", test.t, test.first, test.second, first, second)
+		}
+	}
+
+	first, second := path.SplitAtLength(15)
+	if first.String() != "M 0 0 L 10 0 L 10 5" || second.String() != "M 10 5 L 10 10 M 20 0 C 20 10 30 10 30 0" {
+		t.Errorf("SplitAtLength: unexpected %v | %v\n", first, second)
+	}
+
+	square, _ := utils.PathParser("M0 0 H10 V10 H0 Z")
+	first, second = square.SplitAtLength(5)
+	if first.String() != "M 0 0 L 5 0" || second.String() != "M 5 0 L 10 0 L 10 10 L 0 10 L 0 0" {
+		t.Errorf("SplitAtLength closed: unexpected %v | %v\n", first, second)
+	}
+}
+
+func TestPathTrim(t *testing.T) {
+	path, _ := utils.PathParser("M0 0 H10 V10 H0 Z M20 0 H30")
+	testCases := []struct {
+		start, end float64
+		expected   string
+	}{
+		{0, 1, "M 0 0 L 10 0 L 10 10 L 0 10 Z M 20 0 L 30 0"},
+		{0, 0.5, "M 0 0 L 10 0 L 10 10 L 5 10"},
+		{0.1, 0.3, "M 5 0 L 10 0 L 10 5"},
+		{0.7, 1, "M 0 5 L 0 0 M 20 0 L 30 0"},
+		{0.5, 0.5, ""},
+	}
+
+	for _, test := range testCases {
+		if actual := path.Trim(test.start, test.end).String(); actual != test.expected {
+			t.Errorf("Trim %v %v: expected %v, actual %v\n", test.start, test.end, test.expected, actual)
+		}
+	}
+
+	arc, _ := utils.PathParser("M0 0 A10 10 0 0 1 20 0")
+	if l := arc.Trim(0.25, 0.75).Length(); math.Abs(l-arc.Length()/2) > 1e-9 {
+		t.Errorf("Trim: expected length %v, actual %v\n", arc.Length()/2, l)
+	}
+}
+
+func TestPathJoinReorder(t *testing.T) {
+	path, _ := utils.PathParser("M0 0 L10 0 M10 0 L10 10 M20 20 L30 30")
+	if actual := path.Join().String(); actual != "M 0 0 L 10 0 L 10 10 L 20 20 L 30 30" {
+		t.Errorf("Join: unexpected %v\n", actual)
+	}
+
+	path, _ = utils.PathParser("M0 0 h10 z l5 5 m10 0 h5")
+	if actual := path.Reorder([]int{2, 0, 1, 7}).String(); actual != "M 15 5 H 20 M 0 0 H 10 Z M 0 0 L 5 5" {
+		t.Errorf("Reorder: unexpected %v\n", actual)
+	}
+}
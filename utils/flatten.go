@@ -0,0 +1,77 @@
+package utils
+
+import "math"
+
+// Polyline is a sequence of connected points. The points of a closed
+// polyline do not repeat the first point at the end.
+type Polyline struct {
+	Points []Point
+	Closed bool
+}
+
+// distanceToLine returns the distance of p to the line through a and b.
+func distanceToLine(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return math.Hypot(p.X-a.X, p.Y-a.Y)
+	}
+	return math.Abs(dx*(p.Y-a.Y)-dy*(p.X-a.X)) / l
+}
+
+// isFlat returns true if the segment deviates at most tolerance from the
+// line segment between its end points.
+func (s Segment) isFlat(tolerance float64) bool {
+	switch s.Symbol {
+	case "L":
+		return true
+	case "A":
+		// sagitta of the arc with the larger radius
+		r := math.Max(s.Arc.RX, s.Arc.RY)
+		return r*(1-math.Cos(math.Abs(s.Arc.Sweep)*math.Pi/360)) <= tolerance
+	}
+	// the curve lies within the convex hull of its control points, which
+	// may overshoot the end points even if they are collinear
+	for _, p := range s.Points[1 : len(s.Points)-1] {
+		if distanceToSegment(p, s.Start(), s.End()) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// flatten appends the points of the segment approximated by lines, excluding
+// its start point.
+func (s Segment) flatten(tolerance float64, points []Point, depth int) []Point {
+	// curves with end points on top of each other are never flat at first
+	if depth < 16 && (!s.isFlat(tolerance) || depth == 0 && s.Symbol != "L" && s.Start() == s.End()) {
+		first, second := s.Split(0.5)
+		points = first.flatten(tolerance, points, depth+1)
+		return second.flatten(tolerance, points, depth+1)
+	}
+	return append(points, s.End())
+}
+
+// Flatten approximates the segment with lines which deviate at most tolerance
+// from it and returns their points, including the start point.
+func (s Segment) Flatten(tolerance float64) []Point {
+	return s.flatten(tolerance, []Point{s.Start()}, 0)
+}
+
+// Flatten approximates the path with polylines, one for every subpath, using
+// adaptive subdivision of curves and arcs. No point of the path deviates more
+// than tolerance from the polylines.
+func (p *Path) Flatten(tolerance float64) []Polyline {
+	var polylines []Polyline
+	for _, c := range p.Contours() {
+		polyline := Polyline{Points: []Point{c.Start}, Closed: c.Closed}
+		for _, s := range c.Segments {
+			polyline.Points = s.flatten(tolerance, polyline.Points, 0)
+		}
+		if n := len(polyline.Points); c.Closed && n > 1 && polyline.Points[n-1] == polyline.Points[0] {
+			polyline.Points = polyline.Points[:n-1]
+		}
+		polylines = append(polylines, polyline)
+	}
+	return polylines
+}
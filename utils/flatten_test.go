@@ -0,0 +1,96 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// distanceToPolyline returns the distance of p to the nearest point of the
+// polyline.
+func distanceToPolyline(p utils.Point, polyline utils.Polyline) float64 {
+	points := polyline.Points
+	if polyline.Closed {
+		points = append(points, points[0])
+	}
+	d := math.Inf(1)
+	for i := 0; i+1 < len(points); i++ {
+		a, b := points[i], points[i+1]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		t := 0.0
+		if l := dx*dx + dy*dy; l > 0 {
+			t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+		}
+		d = math.Min(d, math.Hypot(a.X+t*dx-p.X, a.Y+t*dy-p.Y))
+	}
+	return d
+}
+
+func TestSegmentSplit(t *testing.T) {
+	path, _ := utils.PathParser("M0 0 C0 10 10 10 10 0 Q15 -10 20 0 A5 5 0 0 1 30 0")
+	for _, s := range path.Contours()[0].Segments {
+		first, second := s.Split(0.3)
+		for _, pair := range [][2]utils.Point{
+			{first.PointAt(0.5), s.PointAt(0.15)},
+			{second.PointAt(0.5), s.PointAt(0.65)},
+			{first.End(), second.Start()},
+		} {
+			if !(closeTo(pair[0].X, pair[1].X, 1e-9) && closeTo(pair[0].Y, pair[1].Y, 1e-9)) {
+				t.Errorf("Split %s: expected %v, actual %v\n", s.Symbol, pair[1], pair[0])
+			}
+		}
+	}
+}
+
+func TestPathFlatten(t *testing.T) {
+	path, _ := utils.PathParser("M0 0 C0 100 100 100 100 0 A50 50 0 1 1 0 0 Z M200 200 L300 200")
+	for _, tolerance := range []float64{1, 0.1, 0.01} {
+		polylines := path.Flatten(tolerance)
+		if len(polylines) != 2 || !polylines[0].Closed || polylines[1].Closed {
+			t.Fatalf("Flatten: unexpected polylines %v\n", polylines)
+		}
+		if n := len(polylines[1].Points); n != 2 {
+			t.Errorf("Flatten: expected %v points for a line, actual %v\n", 2, n)
+		}
+		first := polylines[0].Points
+		if first[0] != (utils.Point{0, 0}) || first[len(first)-1] == first[0] {
+			t.Errorf("Flatten: closed polyline should not repeat its start, actual %v\n", first)
+		}
+
+		for _, s := range path.Contours()[0].Segments {
+			for i := 0; i <= 100; i++ {
+				p := s.PointAt(float64(i) / 100)
+				if d := distanceToPolyline(p, polylines[0]); d > tolerance {
+					t.Errorf("Flatten: point %v deviates %v more than %v\n", p, d, tolerance)
+				}
+			}
+		}
+	}
+
+	coarse, fine := path.Flatten(1), path.Flatten(0.01)
+	if len(coarse[0].Points) >= len(fine[0].Points) {
+		t.Errorf("Flatten: expected more points for a smaller tolerance")
+	}
+}
+
+func TestPathFlattenOvershoot(t *testing.T) {
+	// collinear control points overshooting the end points
+	path, _ := utils.PathParser("M0 0 C20 0 -15 0 5 0")
+	tolerance := 0.01
+	polyline := path.Flatten(tolerance)[0]
+	s := path.Contours()[0].Segments[0]
+	for i := 0; i <= 100; i++ {
+		p := s.PointAt(float64(i) / 100)
+		if d := distanceToPolyline(p, polyline); d > tolerance {
+			t.Errorf("Flatten: point %v deviates %v more than %v\n", p, d, tolerance)
+		}
+	}
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, p := range polyline.Points {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+	}
+	if math.Abs(minX+1.42) > 0.01 || math.Abs(maxX-6.42) > 0.01 {
+		t.Errorf("Flatten: expected x in [%v, %v], actual [%v, %v]\n", -1.42, 6.42, minX, maxX)
+	}
+}
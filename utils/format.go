@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CommandForm selects whether path commands are written in absolute or
+// relative form.
+type CommandForm int
+
+const (
+	// OriginalForm keeps the form each command was given in.
+	OriginalForm CommandForm = iota
+	// AbsoluteForm writes all commands in absolute form.
+	AbsoluteForm
+	// RelativeForm writes all commands in relative form.
+	RelativeForm
+	// ShortestForm writes every command in the form with the shorter output.
+	ShortestForm
+)
+
+// FormatOptions controls the serialization of a path.
+type FormatOptions struct {
+	// Precision is the maximum number of decimals, or -1 for the shortest
+	// representation which reads back to the exact value.
+	Precision int
+	// Compact omits all separators which are not required, e.g. "M1-2.5.5".
+	Compact bool
+	// ElideRepeated omits command letters which repeat the previous command,
+	// including lineto after moveto.
+	ElideRepeated bool
+	// Form selects absolute or relative commands.
+	Form CommandForm
+}
+
+// formatter writes path tokens with the separators required by the options.
+type formatter struct {
+	opts FormatOptions
+	b    strings.Builder
+	last string
+}
+
+func (f *formatter) number(n float64) string {
+	var s string
+	if f.opts.Precision < 0 {
+		s = strconv.FormatFloat(n, 'f', -1, 64)
+	} else {
+		s = strconv.FormatFloat(n, 'f', f.opts.Precision, 64)
+		if strings.Contains(s, ".") {
+			s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		}
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	if f.opts.Compact {
+		if strings.HasPrefix(s, "0.") {
+			s = s[1:]
+		} else if strings.HasPrefix(s, "-0.") {
+			s = "-" + s[2:]
+		}
+	}
+	return s
+}
+
+// needsSeparator reports whether a separator is required between the last
+// written token and the next one.
+func (f *formatter) needsSeparator(next string) bool {
+	if f.last == "" {
+		return false
+	}
+	if !f.opts.Compact {
+		return true
+	}
+	if isCommandLetter(f.last) || isCommandLetter(next) {
+		return false
+	}
+	if strings.HasPrefix(next, "-") {
+		return false
+	}
+	if strings.HasPrefix(next, ".") && strings.Contains(f.last, ".") {
+		return false
+	}
+	return true
+}
+
+func (f *formatter) write(token string) {
+	if f.needsSeparator(token) {
+		f.b.WriteByte(' ')
+	}
+	f.b.WriteString(token)
+	f.last = token
+}
+
+func isCommandLetter(token string) bool {
+	return len(token) == 1 && strings.ContainsAny(token, "MmZzLlHhVvCcSsQqTtAa")
+}
+
+// relativeParams returns the parameters of an absolute command relative to
+// the current point.
+func relativeParams(symbol string, params []float64, cur Point) []float64 {
+	rel := append([]float64{}, params...)
+	switch symbol {
+	case "H":
+		rel[0] -= cur.X
+	case "V":
+		rel[0] -= cur.Y
+	case "A":
+		rel[5] -= cur.X
+		rel[6] -= cur.Y
+	default:
+		for i := 0; i+1 < len(rel); i += 2 {
+			rel[i] -= cur.X
+			rel[i+1] -= cur.Y
+		}
+	}
+	return rel
+}
+
+// Format returns the path as value of a 'd' attribute according to the
+// options. Relative coordinates are computed from the rounded output, so
+// rounding errors do not accumulate.
+func (p *Path) Format(opts FormatOptions) string {
+	f := &formatter{opts: opts}
+	abs := p.ToAbsolute()
+	var cur, start Point
+	previous := ""
+	for i, subpath := range abs.Subpaths {
+		for j, c := range subpath.Commands {
+			original := p.Subpaths[i].Commands[j]
+			if c.Symbol == "Z" {
+				symbol := "Z"
+				if opts.Form == RelativeForm || opts.Form == OriginalForm && !original.IsAbsolute() {
+					symbol = "z"
+				}
+				f.write(symbol)
+				previous, cur = symbol, start
+				continue
+			}
+
+			lower := strings.ToLower(c.Symbol)
+			absolute := make([]string, len(c.Params))
+			relative := make([]string, len(c.Params))
+			rel := relativeParams(c.Symbol, c.Params, cur)
+			if opts.Precision < 0 && !original.IsAbsolute() {
+				rel = original.Params
+			}
+			for k := range c.Params {
+				absolute[k] = f.number(c.Params[k])
+				relative[k] = f.number(rel[k])
+			}
+			if c.Symbol == "A" {
+				for _, k := range []int{3, 4} {
+					absolute[k] = f.number(math.Abs(math.Round(c.Params[k])))
+					relative[k] = absolute[k]
+				}
+			}
+
+			useRelative := false
+			switch opts.Form {
+			case OriginalForm:
+				useRelative = !original.IsAbsolute()
+			case RelativeForm:
+				useRelative = true
+			case ShortestForm:
+				useRelative = len(strings.Join(relative, " ")) < len(strings.Join(absolute, " "))
+			}
+			symbol, tokens := c.Symbol, absolute
+			if useRelative {
+				symbol, tokens = lower, relative
+			}
+
+			elide := opts.ElideRepeated && (symbol == previous && symbol != "M" && symbol != "m" ||
+				previous == "M" && symbol == "L" || previous == "m" && symbol == "l")
+			if !elide {
+				f.write(symbol)
+			}
+			for _, t := range tokens {
+				f.write(t)
+			}
+			previous = symbol
+
+			// track the current point as a reader of the output would see it
+			values := make([]float64, len(tokens))
+			for k, t := range tokens {
+				values[k], _ = strconv.ParseFloat(t, 64)
+			}
+			var base Point
+			if useRelative {
+				base = cur
+			}
+			switch c.Symbol {
+			case "H":
+				cur.X = base.X + values[0]
+			case "V":
+				cur.Y = base.Y + values[0]
+			default:
+				cur = Point{base.X + values[len(values)-2], base.Y + values[len(values)-1]}
+			}
+			if c.Symbol == "M" {
+				start = cur
+			}
+		}
+	}
+	return f.b.String()
+}
+
+// String returns the path as value of a 'd' attribute.
+func (p *Path) String() string {
+	return p.Format(FormatOptions{Precision: -1})
+}
@@ -0,0 +1,67 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestPathString(t *testing.T) {
+	path, _ := utils.PathParser("M10,20 l 0.5,-3 H 5 z")
+	expected := "M 10 20 l 0.5 -3 H 5 z"
+	if s := path.String(); s != expected {
+		t.Errorf("String: expected %q, actual %q\n", expected, s)
+	}
+}
+
+func TestPathFormat(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		opts     utils.FormatOptions
+		expected string
+	}{
+		{
+			"M 1 -2 L 0.5 0.5",
+			utils.FormatOptions{Precision: -1, Compact: true},
+			"M1-2L.5.5",
+		},
+		{
+			"M 1 2 L 3 4 L 5 6 L 7 8",
+			utils.FormatOptions{Precision: -1, Compact: true, ElideRepeated: true},
+			"M1 2 3 4 5 6 7 8",
+		},
+		{
+			"M 0.123456 1.98765 L 3 4",
+			utils.FormatOptions{Precision: 2},
+			"M 0.12 1.99 L 3 4",
+		},
+		{
+			"M 100 100 L 101 101 L 0 0",
+			utils.FormatOptions{Precision: -1, Form: utils.ShortestForm},
+			"M 100 100 l 1 1 L 0 0",
+		},
+		{
+			"M 10 10 h 5 v 5 Z",
+			utils.FormatOptions{Precision: -1, Form: utils.AbsoluteForm},
+			"M 10 10 H 15 V 15 Z",
+		},
+		{
+			"M 10 10 L 15 10 A 5 5 0 0 1 20 20",
+			utils.FormatOptions{Precision: -1, Form: utils.RelativeForm, Compact: true},
+			"m10 10l5 0a5 5 0 0 1 5 10",
+		},
+		{
+			// relative output is computed from rounded positions
+			"M 0.4 0 L 0.8 0 L 1.2 0",
+			utils.FormatOptions{Precision: 0, Form: utils.RelativeForm},
+			"m 0 0 l 1 0 l 0 0",
+		},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.d)
+		if s := path.Format(test.opts); s != test.expected {
+			t.Errorf("Format: expected %q, actual %q\n", test.expected, s)
+		}
+	}
+}
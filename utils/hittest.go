@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// Stroke describes the stroke of a path for hit testing. LineJoin and LineCap
+// take the values of the 'stroke-linejoin' and 'stroke-linecap' properties.
+// Empty values and a zero MiterLimit mean the initial values miter, butt and
+// 4.
+type Stroke struct {
+	Width      float64
+	LineJoin   string
+	LineCap    string
+	MiterLimit float64
+}
+
+// crossing returns the parameter in [t0, t1] of the segment at which it
+// reaches the height y, where the segment is monotonic in y on that range.
+func (s Segment) crossing(y, t0, t1 float64) float64 {
+	increasing := s.PointAt(t1).Y > s.PointAt(t0).Y
+	for i := 0; i < 60; i++ {
+		t := (t0 + t1) / 2
+		if (s.PointAt(t).Y < y) == increasing {
+			t0 = t
+		} else {
+			t1 = t
+		}
+	}
+	return (t0 + t1) / 2
+}
+
+// winding returns the winding number of the segment around p, counting
+// crossings of a ray from p towards positive x.
+func (s Segment) winding(p Point) int {
+	ts := append([]float64{0, 1}, s.extrema()...)
+	sort.Float64s(ts)
+	w := 0
+	for i := 1; i < len(ts); i++ {
+		a, b := s.PointAt(ts[i-1]), s.PointAt(ts[i])
+		if (a.Y <= p.Y) == (b.Y <= p.Y) {
+			continue
+		}
+		x := a.X + (p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+		if s.Symbol != "L" {
+			x = s.PointAt(s.crossing(p.Y, ts[i-1], ts[i])).X
+		}
+		if x > p.X {
+			if b.Y > a.Y {
+				w++
+			} else {
+				w--
+			}
+		}
+	}
+	return w
+}
+
+// Winding returns the winding number of the path around the point. Open
+// subpaths are closed with a line, as they are when filled.
+func (p *Path) Winding(x, y float64) int {
+	point := Point{x, y}
+	w := 0
+	for _, c := range p.Contours() {
+		for _, s := range c.Segments {
+			w += s.winding(point)
+		}
+		if end := c.End(); !c.Closed && end != c.Start {
+			w += Segment{"L", []Point{end, c.Start}, Arc{}}.winding(point)
+		}
+	}
+	return w
+}
+
+// Contains returns true if the point is inside the fill of the path
+// according to the fill rule. Curves and arcs are evaluated exactly.
+func (p *Path) Contains(x, y float64, rule FillRule) bool {
+	return rule.inside(p.Winding(x, y))
+}
+
+// inConvex returns true if p is inside or on the convex polygon.
+func inConvex(p Point, polygon ...Point) bool {
+	sign := 0.0
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		c := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+		if c == 0 {
+			continue
+		}
+		if sign != 0 && c*sign < 0 {
+			return false
+		}
+		sign = c
+	}
+	return true
+}
+
+// inBody returns true if p is within half the width of the line from a to b,
+// excluding the areas beyond its end points.
+func inBody(p, a, b Point, half float64) bool {
+	d := sub(b, a)
+	l := dot(d, d)
+	if l == 0 {
+		return false
+	}
+	t := dot(sub(p, a), d) / l
+	return t >= 0 && t <= 1 && distanceToLine(p, a, b) <= half
+}
+
+// inJoin returns true if p is inside the line join at vertex v between the
+// unit directions in and out.
+func (s Stroke) inJoin(p, v, in, out Point, half float64) bool {
+	if s.LineJoin == "round" {
+		return math.Hypot(p.X-v.X, p.Y-v.Y) <= half
+	}
+	cross := in.X*out.Y - in.Y*out.X
+	if math.Abs(cross) < 1e-12 {
+		return false
+	}
+	// offsets of both lines on the outer side of the turn
+	sign := math.Copysign(1, cross)
+	o1 := Point{in.Y * sign * half, -in.X * sign * half}
+	o2 := Point{out.Y * sign * half, -out.X * sign * half}
+	if s.LineJoin != "bevel" {
+		limit := s.MiterLimit
+		if limit == 0 {
+			limit = 4
+		}
+		turn := math.Atan2(math.Abs(cross), dot(in, out))
+		// the ratio of miter length to stroke width is 1/sin(theta/2) for the
+		// angle theta between the segments
+		if 1/math.Sin((math.Pi-turn)/2) <= limit {
+			miter := add(add(v, o1), scaled(in, half*math.Tan(turn/2)))
+			return inConvex(p, v, add(v, o1), miter, add(v, o2))
+		}
+	}
+	return inConvex(p, v, add(v, o1), add(v, o2))
+}
+
+// inCap returns true if p is inside the line cap at end point v of a line
+// leaving in the unit direction out.
+func (s Stroke) inCap(p, v, out Point, half float64) bool {
+	switch s.LineCap {
+	case "round":
+		return math.Hypot(p.X-v.X, p.Y-v.Y) <= half
+	case "square":
+		d := sub(p, v)
+		along, across := dot(d, out), math.Abs(d.X*out.Y-d.Y*out.X)
+		return along >= 0 && along <= half && across <= half
+	}
+	return false
+}
+
+// StrokeContains returns true if the point is inside the stroke of the path,
+// taking the stroke width, line joins and line caps into account. Dashing
+// is not considered.
+func (p *Path) StrokeContains(x, y float64, stroke Stroke) bool {
+	half := stroke.Width / 2
+	if half <= 0 {
+		return false
+	}
+	point := Point{x, y}
+	tolerance := half / 100
+	for _, c := range p.Contours() {
+		var segments []Segment
+		for _, s := range c.Segments {
+			if !s.isDegenerate(0) {
+				segments = append(segments, s)
+			}
+		}
+		if len(segments) == 0 {
+			// subpaths of zero length, from zero length commands or a
+			// closepath, are drawn as caps facing along the x axis
+			if (len(c.Segments) > 0 || c.Closed) && (stroke.inCap(point, c.Start, Point{1, 0}, half) ||
+				stroke.inCap(point, c.Start, Point{-1, 0}, half)) {
+				return true
+			}
+			continue
+		}
+
+		for i, s := range segments {
+			points := s.Flatten(tolerance)
+			for j := 1; j < len(points); j++ {
+				if inBody(point, points[j-1], points[j], half) {
+					return true
+				}
+				// the vertices of a flattened curve are smooth
+				if j < len(points)-1 && math.Hypot(points[j].X-x, points[j].Y-y) <= half {
+					return true
+				}
+			}
+			if i > 0 && stroke.inJoin(point, s.Start(), segments[i-1].Tangent(1), s.Tangent(0), half) {
+				return true
+			}
+		}
+
+		first, last := segments[0], segments[len(segments)-1]
+		if c.Closed {
+			if stroke.inJoin(point, first.Start(), last.Tangent(1), first.Tangent(0), half) {
+				return true
+			}
+		} else if stroke.inCap(point, first.Start(), scaled(first.Tangent(0), -1), half) ||
+			stroke.inCap(point, last.End(), last.Tangent(1), half) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestPathContains(t *testing.T) {
+	testCases := []struct {
+		path     string
+		x, y     float64
+		rule     utils.FillRule
+		expected bool
+	}{
+		{"M0 0 H10 V10 H0 Z", 5, 5, utils.NonZero, true},
+		{"M0 0 H10 V10 H0 Z", 15, 5, utils.NonZero, false},
+		// open subpaths are filled as if closed
+		{"M0 0 H10 V10", 8, 2, utils.NonZero, true},
+		{"M0 0 H10 V10", 2, 8, utils.NonZero, false},
+		// nested squares in the same direction
+		{"M0 0 H10 V10 H0 Z M3 3 H7 V7 H3 Z", 5, 5, utils.NonZero, true},
+		{"M0 0 H10 V10 H0 Z M3 3 H7 V7 H3 Z", 5, 5, utils.EvenOdd, false},
+		{"M0 0 H10 V10 H0 Z M3 3 V7 H7 V3 Z", 5, 5, utils.NonZero, false},
+		// curves are exact rather than flattened
+		{"M0 0 C0 13.333 10 13.333 10 0 Z", 5, 9.99, utils.NonZero, true},
+		{"M0 0 C0 13.333 10 13.333 10 0 Z", 5, 10.01, utils.NonZero, false},
+		{"M0 10 A10 10 0 0 1 20 10 A10 10 0 0 1 0 10 Z", 10, 19.99, utils.NonZero, true},
+		{"M0 10 A10 10 0 0 1 20 10 A10 10 0 0 1 0 10 Z", 17.1, 17.1, utils.NonZero, false},
+		{"M0 10 A10 10 0 0 1 20 10 A10 10 0 0 1 0 10 Z", 17, 17, utils.NonZero, true},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.path)
+		if actual := path.Contains(test.x, test.y, test.rule); actual != test.expected {
+			t.Errorf("Contains %q (%v, %v): expected %v, actual %v\n", test.path, test.x, test.y, test.expected, actual)
+		}
+	}
+}
+
+func TestPathStrokeContains(t *testing.T) {
+	corner := "M0 0 H10 V10"
+	testCases := []struct {
+		path     string
+		x, y     float64
+		stroke   utils.Stroke
+		expected bool
+	}{
+		{corner, 5, 0.9, utils.Stroke{Width: 2}, true},
+		{corner, 5, 1.1, utils.Stroke{Width: 2}, false},
+		// caps
+		{corner, -0.5, 0, utils.Stroke{Width: 2}, false},
+		{corner, -0.5, 0.5, utils.Stroke{Width: 2, LineCap: "round"}, true},
+		{corner, -0.9, 0.9, utils.Stroke{Width: 2, LineCap: "round"}, false},
+		{corner, -0.9, 0.9, utils.Stroke{Width: 2, LineCap: "square"}, true},
+		{corner, 10, 10.9, utils.Stroke{Width: 2, LineCap: "square"}, true},
+		// joins at the outer corner
+		{corner, 10.9, -0.9, utils.Stroke{Width: 2}, true},
+		{corner, 10.9, -0.9, utils.Stroke{Width: 2, LineJoin: "bevel"}, false},
+		{corner, 10.6, -0.6, utils.Stroke{Width: 2, LineJoin: "round"}, true},
+		{corner, 10.9, -0.9, utils.Stroke{Width: 2, LineJoin: "round"}, false},
+		{corner, 10.9, -0.9, utils.Stroke{Width: 2, MiterLimit: 1.2}, false},
+		// closed subpaths have a join instead of caps
+		{"M0 0 H10 V10 H0 Z", -0.9, -0.9, utils.Stroke{Width: 2}, true},
+		{"M0 0 H10 V10 H0 V0", -0.9, -0.9, utils.Stroke{Width: 2}, false},
+		// curves
+		{"M0 10 A10 10 0 0 1 20 10", 10, 0.5, utils.Stroke{Width: 2}, true},
+		{"M0 10 A10 10 0 0 1 20 10", 10, 1.5, utils.Stroke{Width: 2}, false},
+		{"M0 0 C0 13.333 10 13.333 10 0", 5, 10.5, utils.Stroke{Width: 2}, true},
+		{"M0 0 C20 0 -15 0 5 0", 6, 0, utils.Stroke{Width: 0.5}, true},
+		{"M0 0 C20 0 -15 0 5 0", -1, 0, utils.Stroke{Width: 0.5}, true},
+		// zero length subpaths
+		{"M5 5 L5 5", 5.5, 5.5, utils.Stroke{Width: 2, LineCap: "round"}, true},
+		{"M5 5 L5 5", 5.5, 5.5, utils.Stroke{Width: 2}, false},
+		{"M5 5 Z", 5.5, 5.5, utils.Stroke{Width: 2, LineCap: "round"}, true},
+		{"M5 5 Z", 5.9, 5.9, utils.Stroke{Width: 2, LineCap: "square"}, true},
+		{"M5 5 Z", 5.5, 5.5, utils.Stroke{Width: 2}, false},
+		{"M0 0 H10 M5 5", 5.5, 5.5, utils.Stroke{Width: 2, LineCap: "round"}, false},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.path)
+		if actual := path.StrokeContains(test.x, test.y, test.stroke); actual != test.expected {
+			t.Errorf("StrokeContains %q (%v, %v) %+v: expected %v, actual %v\n", test.path, test.x, test.y, test.stroke, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// IntersectionPoint is a point shared by two segments or paths together with
+// its parameter on each of them.
+type IntersectionPoint struct {
+	Point  Point
+	T1, T2 float64
+}
+
+// hull returns a box containing the segment, which for curves is the box of
+// their control points.
+func (s Segment) hull() Box {
+	if s.Symbol == "A" {
+		return s.Bounds()
+	}
+	b := EmptyBox()
+	for _, p := range s.Points {
+		b = b.Extend(p)
+	}
+	return b
+}
+
+func (b Box) overlaps(o Box, slack float64) bool {
+	return b.Min.X <= o.Max.X+slack && o.Min.X <= b.Max.X+slack &&
+		b.Min.Y <= o.Max.Y+slack && o.Min.Y <= b.Max.Y+slack
+}
+
+// piece is the part of a segment between two of its parameters.
+type piece struct {
+	Segment
+	t0, t1 float64
+}
+
+func (p piece) split() (piece, piece) {
+	mid := (p.t0 + p.t1) / 2
+	a, b := p.Segment.Split(0.5)
+	return piece{a, p.t0, mid}, piece{b, mid, p.t1}
+}
+
+// lineParameters returns the parameters of the intersection of the lines
+// from a0 to a1 and from b0 to b1, and whether they are not parallel.
+func lineParameters(a0, a1, b0, b1 Point) (float64, float64, bool) {
+	d1, d2 := sub(a1, a0), sub(b1, b0)
+	den := d1.X*d2.Y - d1.Y*d2.X
+	if math.Abs(den) <= 1e-14*math.Hypot(d1.X, d1.Y)*math.Hypot(d2.X, d2.Y) || den == 0 {
+		return 0, 0, false
+	}
+	w := sub(b0, a0)
+	return (w.X*d2.Y - w.Y*d2.X) / den, (w.X*d1.Y - w.Y*d1.X) / den, true
+}
+
+// refine improves the parameters of an intersection of two segments with
+// Newton's method.
+func refine(s, o Segment, t, u float64) (float64, float64) {
+	for i := 0; i < 8; i++ {
+		f := sub(s.PointAt(t), o.PointAt(u))
+		if f.X == 0 && f.Y == 0 {
+			break
+		}
+		d1, d2 := s.Derivative(t), o.Derivative(u)
+		// solve d1*dt - d2*du = -f
+		det := -d1.X*d2.Y + d1.Y*d2.X
+		if det == 0 {
+			break
+		}
+		dt := (f.X*d2.Y - f.Y*d2.X) / det
+		du := (d1.Y*f.X - d1.X*f.Y) / det
+		nt, nu := t+dt, u+du
+		if nt < 0 || nt > 1 || nu < 0 || nu > 1 {
+			break
+		}
+		t, u = nt, nu
+	}
+	return t, u
+}
+
+// intersectPieces appends the intersections of two pieces found by recursive
+// subdivision until both are flat.
+func intersectPieces(s, o Segment, a, b piece, tolerance float64, depth int, found []IntersectionPoint) []IntersectionPoint {
+	if !a.hull().overlaps(b.hull(), tolerance) {
+		return found
+	}
+	flatA, flatB := a.Symbol == "L" || a.isFlat(tolerance), b.Symbol == "L" || b.isFlat(tolerance)
+	if flatA && flatB || depth >= 48 {
+		ta, tb, ok := lineParameters(a.Start(), a.End(), b.Start(), b.End())
+		const slack = 1e-9
+		if !ok || ta < -slack || ta > 1+slack || tb < -slack || tb > 1+slack {
+			return found
+		}
+		t := a.t0 + math.Max(0, math.Min(1, ta))*(a.t1-a.t0)
+		u := b.t0 + math.Max(0, math.Min(1, tb))*(b.t1-b.t0)
+		if s.Symbol != "L" || o.Symbol != "L" {
+			t, u = refine(s, o, t, u)
+		}
+		return append(found, IntersectionPoint{s.PointAt(t), t, u})
+	}
+
+	if !flatA && (flatB || a.hull().Width()+a.hull().Height() >= b.hull().Width()+b.hull().Height()) {
+		a1, a2 := a.split()
+		found = intersectPieces(s, o, a1, b, tolerance, depth+1, found)
+		return intersectPieces(s, o, a2, b, tolerance, depth+1, found)
+	}
+	b1, b2 := b.split()
+	found = intersectPieces(s, o, a, b1, tolerance, depth+1, found)
+	return intersectPieces(s, o, a, b2, tolerance, depth+1, found)
+}
+
+// dedupe sorts intersections by their first parameter and removes those
+// which are closer than tolerance to one kept before, which may be far away
+// in parameters, as at the start and end of a closed subpath.
+func dedupe(found []IntersectionPoint, tolerance float64) []IntersectionPoint {
+	sort.Slice(found, func(i, j int) bool { return found[i].T1 < found[j].T1 })
+	var unique []IntersectionPoint
+	for _, f := range found {
+		duplicate := false
+		for _, u := range unique {
+			duplicate = duplicate || math.Hypot(f.Point.X-u.Point.X, f.Point.Y-u.Point.Y) <= tolerance
+		}
+		if !duplicate {
+			unique = append(unique, f)
+		}
+	}
+	return unique
+}
+
+// intersectionTolerance returns the tolerance for intersections of segments
+// of the size of the box.
+func intersectionTolerance(b Box) float64 {
+	return 1e-9 * math.Max(1, math.Max(b.Width(), b.Height()))
+}
+
+// Intersections returns the points where the segment meets the other
+// segment, ordered by their parameter on the segment. Lines, quadratic and
+// cubic curves and arcs are subdivided until they are flat and the results
+// are refined with Newton's method. Overlapping segments report the points
+// where the overlap starts and ends.
+func (s Segment) Intersections(o Segment) []IntersectionPoint {
+	tolerance := intersectionTolerance(s.hull().Union(o.hull()))
+	var found []IntersectionPoint
+	if s.Symbol == "L" && o.Symbol == "L" {
+		if t, u, ok := lineParameters(s.Start(), s.End(), o.Start(), o.End()); ok {
+			if t >= 0 && t <= 1 && u >= 0 && u <= 1 {
+				found = append(found, IntersectionPoint{s.PointAt(t), t, u})
+			}
+			return found
+		}
+	}
+	if s.Symbol == "L" && o.Symbol == "L" || s.overlaps(o, tolerance) {
+		// collinear lines or coincident curves meet where either ends on the other
+		for _, p := range []struct {
+			point Point
+			t     float64
+			other bool
+		}{{s.Start(), 0, false}, {s.End(), 1, false}, {o.Start(), 0, true}, {o.End(), 1, true}} {
+			if p.other {
+				if t, d := s.Nearest(p.point); d <= tolerance {
+					found = append(found, IntersectionPoint{p.point, t, p.t})
+				}
+			} else if u, d := o.Nearest(p.point); d <= tolerance {
+				found = append(found, IntersectionPoint{p.point, p.t, u})
+			}
+		}
+		return dedupe(found, tolerance)
+	}
+	found = intersectPieces(s, o, piece{s, 0, 1}, piece{o, 0, 1}, tolerance, 0, nil)
+	return dedupe(found, tolerance*1e3)
+}
+
+// overlaps returns true if a part of the segment lies on the other segment,
+// tested at a few points between the end points of both.
+func (s Segment) overlaps(o Segment, tolerance float64) bool {
+	on := 0
+	for _, t := range []float64{0.1, 0.3, 0.5, 0.7, 0.9} {
+		if _, d := o.Nearest(s.PointAt(t)); d <= tolerance {
+			on++
+		}
+		if _, d := s.Nearest(o.PointAt(t)); d <= tolerance {
+			on++
+		}
+	}
+	return on >= 3
+}
+
+// Nearest returns the parameter of the point of the segment nearest to p and
+// its distance to p.
+func (s Segment) Nearest(p Point) (float64, float64) {
+	distance := func(t float64) float64 {
+		q := s.PointAt(t)
+		return math.Hypot(q.X-p.X, q.Y-p.Y)
+	}
+	if s.Symbol == "L" {
+		d := sub(s.End(), s.Start())
+		t := 0.0
+		if l := dot(d, d); l > 0 {
+			t = math.Max(0, math.Min(1, dot(sub(p, s.Start()), d)/l))
+		}
+		return t, distance(t)
+	}
+
+	// sample the segment and refine around the best samples, which are in
+	// different local minima
+	const samples = 32
+	best, bestDistance := 0.0, math.Inf(1)
+	for i := 0; i <= samples; i++ {
+		t := float64(i) / samples
+		if d := distance(t); d < bestDistance+1e-12 {
+			low, high := math.Max(0, t-1.0/samples), math.Min(1, t+1.0/samples)
+			// golden section search for the minimum within the bracket
+			for j := 0; j < 80; j++ {
+				m1, m2 := low+(high-low)*0.381966, low+(high-low)*0.618034
+				if distance(m1) < distance(m2) {
+					high = m2
+				} else {
+					low = m1
+				}
+			}
+			t = (low + high) / 2
+			if d := distance(t); d < bestDistance {
+				best, bestDistance = t, d
+			}
+		}
+	}
+	return best, bestDistance
+}
+
+// pathSegments returns the segments of all contours of the path in order,
+// which is the order of path parameters.
+func (p *Path) pathSegments() []Segment {
+	var segments []Segment
+	for _, c := range p.Contours() {
+		segments = append(segments, c.Segments...)
+	}
+	return segments
+}
+
+// Intersections returns the points where the path meets the other path. The
+// parameters are path parameters as used by SplitAt: their integer part
+// selects the segment and their fraction the parameter on it.
+func (p *Path) Intersections(o *Path) []IntersectionPoint {
+	segments, others := p.pathSegments(), o.pathSegments()
+	var found []IntersectionPoint
+	for i, s := range segments {
+		bounds := s.hull()
+		for j, other := range others {
+			if !bounds.overlaps(other.hull(), intersectionTolerance(bounds)) {
+				continue
+			}
+			for _, f := range s.Intersections(other) {
+				found = append(found, IntersectionPoint{f.Point, float64(i) + f.T1, float64(j) + f.T2})
+			}
+		}
+	}
+	// intersections at shared end points of consecutive segments, including
+	// the start and end of closed subpaths, are found twice
+	return dedupe(found, intersectionTolerance(p.Bounds().Union(o.Bounds()))*1e3)
+}
+
+// NearestPoint returns the point of the path nearest to (x, y), its path
+// parameter as used by SplitAt and its distance. For an empty path the
+// distance is infinite.
+func (p *Path) NearestPoint(x, y float64) (Point, float64, float64) {
+	point := Point{x, y}
+	var nearest Point
+	param, distance := 0.0, math.Inf(1)
+	for i, s := range p.pathSegments() {
+		if t, d := s.Nearest(point); d < distance {
+			nearest, param, distance = s.PointAt(t), float64(i)+t, d
+		}
+	}
+	return nearest, param, distance
+}
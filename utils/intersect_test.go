@@ -0,0 +1,119 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func segment(t *testing.T, d string) utils.Segment {
+	path, err := utils.PathParser(d)
+	if err != nil {
+		t.Fatalf("PathParser %q failed: %v\n", d, err)
+	}
+	return path.Contours()[0].Segments[0]
+}
+
+func TestSegmentIntersections(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected []utils.Point
+	}{
+		{"M0 0 L10 10", "M0 10 L10 0", []utils.Point{{5, 5}}},
+		{"M0 0 L10 10", "M20 0 L30 10", nil},
+		{"M0 0 L10 0", "M5 0 L20 0", []utils.Point{{5, 0}, {10, 0}}},
+		{"M0 0 L10 0", "M10 0 L10 10", []utils.Point{{10, 0}}},
+		{"M0 5 L20 5", "M0 0 Q10 20 20 0", []utils.Point{{2.9289321881345245, 5}, {17.071067811865476, 5}}},
+		{"M0 0 C0 10 10 10 10 0", "M0 5 L10 5", []utils.Point{{1.1509982054024945, 5}, {8.849001794597505, 5}}},
+		{"M-10 0 L10 0", "M0 -5 A5 5 0 0 1 0 5", []utils.Point{{5, 0}}},
+		{"M0 0 A10 10 0 0 1 20 0", "M0 0 A10 10 0 0 0 20 0", []utils.Point{{0, 0}, {20, 0}}},
+		{"M0 0 C10 10 20 -10 30 0", "M0 0 C10 -10 20 10 30 0", []utils.Point{{0, 0}, {15, 0}, {30, 0}}},
+	}
+
+	for _, test := range testCases {
+		a, b := segment(t, test.a), segment(t, test.b)
+		actual := a.Intersections(b)
+		if len(actual) != len(test.expected) {
+			t.Errorf("Intersections %q %q: expected %v, actual %v\n", test.a, test.b, test.expected, actual)
+			continue
+		}
+		for i, f := range actual {
+			if !closeTo(f.Point.X, test.expected[i].X, 1e-6) || !closeTo(f.Point.Y, test.expected[i].Y, 1e-6) {
+				t.Errorf("Intersections %q %q: expected %v, actual %v\n", test.a, test.b, test.expected[i], f.Point)
+			}
+			p1, p2 := a.PointAt(f.T1), b.PointAt(f.T2)
+			if math.Hypot(p1.X-p2.X, p1.Y-p2.Y) > 1e-6 {
+				t.Errorf("Intersections %q %q: parameters %v and %v give different points\n", test.a, test.b, f.T1, f.T2)
+			}
+		}
+	}
+}
+
+func TestPathIntersections(t *testing.T) {
+	a, _ := utils.PathParser("M0 0 H10 V10 H0 Z")
+	b, _ := utils.PathParser("M5 -5 V15 M-5 5 H15")
+	actual := a.Intersections(b)
+	expected := []utils.IntersectionPoint{
+		{Point: utils.Point{X: 5, Y: 0}, T1: 0.5, T2: 0.25},
+		{Point: utils.Point{X: 10, Y: 5}, T1: 1.5, T2: 1.75},
+		{Point: utils.Point{X: 5, Y: 10}, T1: 2.5, T2: 0.75},
+		{Point: utils.Point{X: 0, Y: 5}, T1: 3.5, T2: 1.25},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("Intersections: expected %v, actual %v\n", expected, actual)
+	}
+	for i := range actual {
+		if !closeTo(actual[i].T1, expected[i].T1, 1e-9) || !closeTo(actual[i].T2, expected[i].T2, 1e-9) ||
+			!closeTo(actual[i].Point.X, expected[i].Point.X, 1e-9) || !closeTo(actual[i].Point.Y, expected[i].Point.Y, 1e-9) {
+			t.Errorf("Intersections: expected %v, actual %v\n", expected[i], actual[i])
+		}
+	}
+
+	// a corner shared by consecutive segments is reported once
+	c, _ := utils.PathParser("M10 -5 L10 5")
+	if n := len(a.Intersections(c)); n != 2 {
+		t.Errorf("Intersections: expected 2 intersections, actual %v\n", n)
+	}
+
+	// as is the start point of a closed subpath, which is also its end
+	circle, _ := utils.PathParser("M0 5 A5 5 0 0 1 10 5 A5 5 0 0 1 0 5 Z")
+	line, _ := utils.PathParser("M-5 5 H15")
+	actual = circle.Intersections(line)
+	if len(actual) != 2 || !closeTo(actual[0].Point.X, 0, 1e-9) || !closeTo(actual[1].Point.X, 10, 1e-9) {
+		t.Errorf("Intersections: expected (0, 5) and (10, 5), actual %v\n", actual)
+	}
+}
+
+func TestNearestPoint(t *testing.T) {
+	testCases := []struct {
+		path     string
+		x, y     float64
+		point    utils.Point
+		param    float64
+		distance float64
+	}{
+		{"M0 0 H10 V10", 5, -3, utils.Point{5, 0}, 0.5, 3},
+		{"M0 0 H10 V10", 13, 5, utils.Point{10, 5}, 1.5, 3},
+		{"M0 0 H10 V10", -3, -4, utils.Point{0, 0}, 0, 5},
+		{"M0 10 A10 10 0 0 1 20 10", 10, 7, utils.Point{10, 0}, 0.5, 7},
+		{"M0 10 A10 10 0 0 1 20 10", 10 + 20*math.Cos(-math.Pi/4), 10 + 20*math.Sin(-math.Pi/4),
+			utils.Point{10 + 10*math.Cos(-math.Pi/4), 10 + 10*math.Sin(-math.Pi/4)}, 0.75, 10},
+		{"M0 0 Q10 20 20 0", 10, 20, utils.Point{10, 10}, 0.5, 10},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.path)
+		point, param, distance := path.NearestPoint(test.x, test.y)
+		if !closeTo(point.X, test.point.X, 1e-6) || !closeTo(point.Y, test.point.Y, 1e-6) ||
+			!closeTo(param, test.param, 1e-6) || !closeTo(distance, test.distance, 1e-6) {
+			t.Errorf("NearestPoint %q (%v, %v): expected %v %v %v, actual %v %v %v\n", test.path, test.x, test.y,
+				test.point, test.param, test.distance, point, param, distance)
+		}
+	}
+
+	empty := &utils.Path{}
+	if _, _, d := empty.NearestPoint(0, 0); !math.IsInf(d, 1) {
+		t.Errorf("NearestPoint: expected infinite distance for an empty path, actual %v\n", d)
+	}
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Length is a number with an optional unit, such as the value of a 'width'
+// attribute. Unit is empty for user units and holds the lower case unit or
+// "%" otherwise.
+type Length struct {
+	Value float64
+	Unit  string
+}
+
+// LengthParserError contains errors which have occured when parsing a
+// length.
+type LengthParserError struct {
+	msg string
+}
+
+func (err LengthParserError) Error() string {
+	return err.msg
+}
+
+// lengthUnits lists the units accepted for lengths.
+var lengthUnits = map[string]bool{
+	"": true, "%": true, "px": true, "em": true, "ex": true, "rem": true,
+	"ch": true, "pt": true, "pc": true, "in": true, "cm": true, "mm": true,
+	"q": true, "vw": true, "vh": true, "vmin": true, "vmax": true,
+}
+
+// LengthParser parses a length, i.e. a number optionally followed by a unit
+// without whitespace in between.
+func LengthParser(raw string) (Length, error) {
+	s := strings.TrimSpace(raw)
+	n := scanNumber(s)
+	if n == 0 {
+		return Length{}, LengthParserError{"invalid length " + strconv.Quote(raw)}
+	}
+	value, err := strconv.ParseFloat(s[:n], 64)
+	if err != nil {
+		return Length{}, LengthParserError{"invalid length " + strconv.Quote(raw)}
+	}
+	unit := strings.ToLower(s[n:])
+	if !lengthUnits[unit] {
+		return Length{}, LengthParserError{"unknown unit in length " + strconv.Quote(raw)}
+	}
+	return Length{value, unit}, nil
+}
+
+// String returns the length as attribute value.
+func (l Length) String() string {
+	return strconv.FormatFloat(l.Value, 'f', -1, 64) + l.Unit
+}
+
+// LengthDirection selects the dimension of the viewport which percentages
+// refer to.
+type LengthDirection int
+
+// Percentages of horizontal lengths such as 'x' and 'width' refer to the
+// viewport width, those of vertical lengths to its height and those of other
+// lengths such as 'r' to its normalized diagonal.
+const (
+	Horizontal LengthDirection = iota
+	Vertical
+	Diagonal
+)
+
+// LengthContext holds what is needed to resolve lengths into user units.
+// Zero values mean the CSS defaults of 96 DPI and a font size of 16 user
+// units, and RootFontSize defaults to FontSize.
+type LengthContext struct {
+	DPI            float64
+	FontSize       float64
+	RootFontSize   float64
+	ViewportWidth  float64
+	ViewportHeight float64
+}
+
+// percentBase returns the size of the viewport in the direction.
+func (c LengthContext) percentBase(direction LengthDirection) float64 {
+	switch direction {
+	case Horizontal:
+		return c.ViewportWidth
+	case Vertical:
+		return c.ViewportHeight
+	}
+	return math.Sqrt((c.ViewportWidth*c.ViewportWidth + c.ViewportHeight*c.ViewportHeight) / 2)
+}
+
+// Resolve returns the length in user units. Absolute units are converted
+// with the DPI, font relative units with the font size, where ex and ch are
+// taken as half an em, and percentages with the viewport size in the
+// direction.
+func (l Length) Resolve(c LengthContext, direction LengthDirection) float64 {
+	dpi, fontSize, rootFontSize := c.DPI, c.FontSize, c.RootFontSize
+	if dpi == 0 {
+		dpi = 96
+	}
+	if fontSize == 0 {
+		fontSize = 16
+	}
+	if rootFontSize == 0 {
+		rootFontSize = fontSize
+	}
+	switch l.Unit {
+	case "in":
+		return l.Value * dpi
+	case "cm":
+		return l.Value * dpi / 2.54
+	case "mm":
+		return l.Value * dpi / 25.4
+	case "q":
+		return l.Value * dpi / 101.6
+	case "pt":
+		return l.Value * dpi / 72
+	case "pc":
+		return l.Value * dpi / 6
+	case "em":
+		return l.Value * fontSize
+	case "ex", "ch":
+		return l.Value * fontSize / 2
+	case "rem":
+		return l.Value * rootFontSize
+	case "%":
+		return l.Value * c.percentBase(direction) / 100
+	case "vw":
+		return l.Value * c.ViewportWidth / 100
+	case "vh":
+		return l.Value * c.ViewportHeight / 100
+	case "vmin":
+		return l.Value * math.Min(c.ViewportWidth, c.ViewportHeight) / 100
+	case "vmax":
+		return l.Value * math.Max(c.ViewportWidth, c.ViewportHeight) / 100
+	}
+	return l.Value
+}
@@ -0,0 +1,67 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestLengthParser(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected utils.Length
+	}{
+		{"10", utils.Length{10, ""}},
+		{" -1.5e2px ", utils.Length{-150, "px"}},
+		{"50%", utils.Length{50, "%"}},
+		{".5EM", utils.Length{0.5, "em"}},
+		{"2.54cm", utils.Length{2.54, "cm"}},
+	}
+
+	for _, test := range testCases {
+		actual, err := utils.LengthParser(test.raw)
+		if err != nil {
+			t.Errorf("LengthParser %q failed: %v\n", test.raw, err)
+		} else if actual != test.expected {
+			t.Errorf("LengthParser %q: expected %v, actual %v\n", test.raw, test.expected, actual)
+		}
+	}
+
+	for _, raw := range []string{"", "px", "10 px", "10furlongs", "1.2.3"} {
+		if _, err := utils.LengthParser(raw); err == nil {
+			t.Errorf("LengthParser %q: expected error\n", raw)
+		}
+	}
+
+	if s := (utils.Length{Value: 12.5, Unit: "mm"}).String(); s != "12.5mm" {
+		t.Errorf("String: expected %q, actual %q\n", "12.5mm", s)
+	}
+}
+
+func TestLengthResolve(t *testing.T) {
+	c := utils.LengthContext{FontSize: 10, ViewportWidth: 300, ViewportHeight: 400}
+	testCases := []struct {
+		length    utils.Length
+		direction utils.LengthDirection
+		expected  float64
+	}{
+		{utils.Length{5, ""}, utils.Horizontal, 5},
+		{utils.Length{1, "in"}, utils.Horizontal, 96},
+		{utils.Length{2.54, "cm"}, utils.Horizontal, 96},
+		{utils.Length{72, "pt"}, utils.Vertical, 96},
+		{utils.Length{1, "pc"}, utils.Vertical, 16},
+		{utils.Length{2, "em"}, utils.Diagonal, 20},
+		{utils.Length{2, "ex"}, utils.Diagonal, 10},
+		{utils.Length{10, "%"}, utils.Horizontal, 30},
+		{utils.Length{10, "%"}, utils.Vertical, 40},
+		{utils.Length{100, "%"}, utils.Diagonal, 353.5533905932738},
+		{utils.Length{10, "vmin"}, utils.Diagonal, 30},
+	}
+
+	for _, test := range testCases {
+		if actual := test.length.Resolve(c, test.direction); math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("Resolve %v: expected %v, actual %v\n", test.length, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"strconv"
+)
+
+// ListParserError contains errors which have occured when parsing a list of
+// numbers or lengths.
+type ListParserError struct {
+	msg string
+}
+
+func (err ListParserError) Error() string {
+	return err.msg
+}
+
+// listItems splits a list of numbers, each optionally followed by a unit if
+// units is set, with the number scanner of the path parser. Items are
+// separated by whitespace with at most one comma, or by nothing where the
+// grammar allows, as in "10-5" or ".5.5".
+func listItems(raw string, units bool) ([]string, error) {
+	var items []string
+	i := 0
+	skip := func() {
+		for i < len(raw) && isWhitespace(raw[i]) {
+			i++
+		}
+	}
+	skip()
+	for i < len(raw) {
+		length := scanNumber(raw[i:])
+		if length == 0 {
+			return nil, ListParserError{"invalid list " + strconv.Quote(raw)}
+		}
+		start := i
+		i += length
+		for units && i < len(raw) && (raw[i] == '%' || raw[i] >= 'a' && raw[i] <= 'z' || raw[i] >= 'A' && raw[i] <= 'Z') {
+			i++
+		}
+		items = append(items, raw[start:i])
+		skip()
+		if i < len(raw) && raw[i] == ',' {
+			i++
+			skip()
+			if i == len(raw) {
+				return nil, ListParserError{"trailing comma in list " + strconv.Quote(raw)}
+			}
+		}
+	}
+	return items, nil
+}
+
+// NumberListParser parses a list of numbers such as the value of a 'points',
+// 'viewBox' or 'rotate' attribute. Numbers are separated by whitespace with
+// at most one comma, or by nothing where the grammar allows, as in "10-5".
+// An empty list gives nil.
+func NumberListParser(raw string) ([]float64, error) {
+	items, err := listItems(raw, false)
+	if err != nil {
+		return nil, err
+	}
+	var numbers []float64
+	for _, item := range items {
+		n, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			return nil, ListParserError{"invalid number " + strconv.Quote(item)}
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+// LengthListParser parses a list of lengths such as the 'x' of a <text>
+// element, separated like the numbers of NumberListParser.
+func LengthListParser(raw string) ([]Length, error) {
+	items, err := listItems(raw, true)
+	if err != nil {
+		return nil, err
+	}
+	var lengths []Length
+	for _, item := range items {
+		l, err := LengthParser(item)
+		if err != nil {
+			return nil, err
+		}
+		lengths = append(lengths, l)
+	}
+	return lengths, nil
+}
@@ -0,0 +1,50 @@
+package utils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestNumberListParser(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected []float64
+	}{
+		{"", nil},
+		{" 10,20 30\t40 ", []float64{10, 20, 30, 40}},
+		{"10-5 20-5", []float64{10, -5, 20, -5}},
+		{".5.5 1e2-1", []float64{0.5, 0.5, 100, -1}},
+		{"1 , 2,3", []float64{1, 2, 3}},
+	}
+
+	for _, test := range testCases {
+		actual, err := utils.NumberListParser(test.raw)
+		if err != nil {
+			t.Errorf("NumberListParser %q failed: %v\n", test.raw, err)
+		} else if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("NumberListParser %q: expected %v, actual %v\n", test.raw, test.expected, actual)
+		}
+	}
+
+	for _, raw := range []string{"a", "1,,2", ",1", "1,", "1px", "1 - 2"} {
+		if _, err := utils.NumberListParser(raw); err == nil {
+			t.Errorf("NumberListParser %q: expected error\n", raw)
+		}
+	}
+}
+
+func TestLengthListParser(t *testing.T) {
+	actual, err := utils.LengthListParser("10px,5em 50%-2")
+	expected := []utils.Length{{Value: 10, Unit: "px"}, {Value: 5, Unit: "em"}, {Value: 50, Unit: "%"}, {Value: -2}}
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("LengthListParser: expected %v, actual %v (%v)\n", expected, actual, err)
+	}
+
+	for _, raw := range []string{"10furlongs", "10 px", "1,"} {
+		if _, err := utils.LengthListParser(raw); err == nil {
+			t.Errorf("LengthListParser %q: expected error\n", raw)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package utils
+
+import "math"
+
+// nodes and weights of the 5-point Gauss-Legendre quadrature on [-1, 1]
+var (
+	gaussNodes   = [5]float64{0, -0.5384693101056831, 0.5384693101056831, -0.9061798459386640, 0.9061798459386640}
+	gaussWeights = [5]float64{0.5688888888888889, 0.4786286704993665, 0.4786286704993665, 0.2369268850561891, 0.2369268850561891}
+)
+
+// lengthTolerance is the relative tolerance of numerically computed lengths.
+const lengthTolerance = 1e-10
+
+func (s Segment) speed(t float64) float64 {
+	d := s.Derivative(t)
+	return math.Hypot(d.X, d.Y)
+}
+
+// gauss integrates the speed of the segment over [a, b].
+func (s Segment) gauss(a, b float64) float64 {
+	half, mid := (b-a)/2, (a+b)/2
+	sum := 0.0
+	for i, x := range gaussNodes {
+		sum += gaussWeights[i] * s.speed(mid+half*x)
+	}
+	return sum * half
+}
+
+func (s Segment) adaptiveLength(a, b, whole float64, depth int) float64 {
+	m := (a + b) / 2
+	left, right := s.gauss(a, m), s.gauss(m, b)
+	if depth >= 20 || math.Abs(left+right-whole) <= lengthTolerance*math.Max(1, whole) {
+		return left + right
+	}
+	return s.adaptiveLength(a, m, left, depth+1) + s.adaptiveLength(m, b, right, depth+1)
+}
+
+// LengthBetween returns the length of the segment between the parameters
+// t0 and t1.
+func (s Segment) LengthBetween(t0, t1 float64) float64 {
+	if t1 <= t0 {
+		return 0
+	}
+	if s.Symbol == "L" {
+		return s.speed(0) * (t1 - t0)
+	}
+	return s.adaptiveLength(t0, t1, s.gauss(t0, t1), 0)
+}
+
+// Length returns the length of the segment.
+func (s Segment) Length() float64 {
+	return s.LengthBetween(0, 1)
+}
+
+// ParameterAtLength returns the parameter t at which the length of the
+// segment from its start equals length.
+func (s Segment) ParameterAtLength(length float64) float64 {
+	total := s.Length()
+	if length <= 0 || total == 0 {
+		return 0
+	}
+	if length >= total {
+		return 1
+	}
+	low, high := 0.0, 1.0
+	t := length / total
+	for i := 0; i < 50; i++ {
+		diff := s.LengthBetween(0, t) - length
+		if math.Abs(diff) <= lengthTolerance*math.Max(1, total) {
+			break
+		}
+		if diff > 0 {
+			high = t
+		} else {
+			low = t
+		}
+		// Newton step, falling back to bisection when it leaves the bracket
+		next := t
+		if v := s.speed(t); v > 0 {
+			next = t - diff/v
+		}
+		if next <= low || next >= high {
+			next = (low + high) / 2
+		}
+		t = next
+	}
+	return t
+}
+
+// Tangent returns the unit tangent of the segment at parameter t.
+func (s Segment) Tangent(t float64) Point {
+	d := s.Derivative(t)
+	// control points coinciding with end points give a zero derivative
+	for _, h := range []float64{1e-6, 1e-3} {
+		if d.X != 0 || d.Y != 0 {
+			break
+		}
+		if t < 0.5 {
+			d = s.Derivative(t + h)
+		} else {
+			d = s.Derivative(t - h)
+		}
+	}
+	l := math.Hypot(d.X, d.Y)
+	if l == 0 {
+		return Point{1, 0}
+	}
+	return Point{d.X / l, d.Y / l}
+}
+
+// Length returns the length of the contour.
+func (c Contour) Length() float64 {
+	length := 0.0
+	for _, s := range c.Segments {
+		length += s.Length()
+	}
+	return length
+}
+
+// Bounds returns the exact bounding box of the contour.
+func (c Contour) Bounds() Box {
+	b := EmptyBox()
+	for _, s := range c.Segments {
+		b = b.Union(s.Bounds())
+	}
+	return b
+}
+
+// Bounds returns the exact bounding box of the path, taking the extrema of
+// curves and arcs into account rather than their control points. Subpaths
+// without segments do not contribute.
+func (p *Path) Bounds() Box {
+	b := EmptyBox()
+	for _, c := range p.Contours() {
+		b = b.Union(c.Bounds())
+	}
+	return b
+}
+
+// Length returns the total length of the path.
+func (p *Path) Length() float64 {
+	length := 0.0
+	for _, c := range p.Contours() {
+		length += c.Length()
+	}
+	return length
+}
+
+// SubpathLengths returns the length of every subpath of the path.
+func (p *Path) SubpathLengths() []float64 {
+	contours := p.Contours()
+	lengths := make([]float64, len(contours))
+	for i, c := range contours {
+		lengths[i] = c.Length()
+	}
+	return lengths
+}
+
+// segmentAtLength returns the segment containing the given distance along
+// the path and the parameter of the distance on that segment. Distances are
+// clamped to the path like getPointAtLength does.
+func (p *Path) segmentAtLength(length float64) (Segment, float64, bool) {
+	var last Segment
+	found := false
+	for _, c := range p.Contours() {
+		for _, s := range c.Segments {
+			l := s.Length()
+			if length <= l {
+				return s, s.ParameterAtLength(length), true
+			}
+			length -= l
+			last, found = s, true
+		}
+	}
+	return last, 1, found
+}
+
+// PointAtLength returns the point at the given distance along the path,
+// matching the behaviour of getPointAtLength in browsers.
+func (p *Path) PointAtLength(length float64) Point {
+	s, t, ok := p.segmentAtLength(length)
+	if !ok {
+		for _, c := range p.Contours() {
+			return c.Start
+		}
+		return Point{}
+	}
+	return s.PointAt(t)
+}
+
+// TangentAtLength returns the unit tangent at the given distance along the
+// path.
+func (p *Path) TangentAtLength(length float64) Point {
+	s, t, ok := p.segmentAtLength(length)
+	if !ok {
+		return Point{1, 0}
+	}
+	return s.Tangent(t)
+}
@@ -0,0 +1,106 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func closeTo(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestPathBounds(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		expected utils.Box
+	}{
+		{"M10 20 L30 5", utils.Box{utils.Point{10, 5}, utils.Point{30, 20}}},
+		{"M0 0 C0 10 10 10 10 0", utils.Box{utils.Point{0, 0}, utils.Point{10, 7.5}}},
+		{"M0 0 Q5 10 10 0", utils.Box{utils.Point{0, 0}, utils.Point{10, 5}}},
+		{"M0 0 A10 10 0 0 1 20 0", utils.Box{utils.Point{0, -10}, utils.Point{20, 0}}},
+		{"M0 0 A10 10 0 0 0 20 0", utils.Box{utils.Point{0, 0}, utils.Point{20, 10}}},
+		{"M0 0 A10 5 90 0 1 0 20", utils.Box{utils.Point{0, 0}, utils.Point{5, 20}}},
+		{"M0 0 h10 M100 100", utils.Box{utils.Point{0, 0}, utils.Point{10, 0}}},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.d)
+		b := path.Bounds()
+		if !(closeTo(b.Min.X, test.expected.Min.X, 1e-9) && closeTo(b.Min.Y, test.expected.Min.Y, 1e-9) &&
+			closeTo(b.Max.X, test.expected.Max.X, 1e-9) && closeTo(b.Max.Y, test.expected.Max.Y, 1e-9)) {
+			t.Errorf("Bounds %s: expected %v, actual %v\n", test.d, test.expected, b)
+		}
+	}
+
+	path, _ := utils.PathParser("M10 10")
+	if !path.Bounds().IsEmpty() {
+		t.Errorf("Bounds: expected empty box, actual %v\n", path.Bounds())
+	}
+}
+
+func TestPathLength(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		expected float64
+	}{
+		{"M0 0 l3 4", 5},
+		{"M0 0 h10 v10 h-10 z", 40},
+		{"M0 0 A10 10 0 0 1 20 0", 10 * math.Pi},
+		{"M0 0 Q5 0 10 0", 10},
+		{"M0 0 C0 0 10 0 10 0", 10},
+		{"M0 0 l10 0 M100 100 l0 5", 15},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.d)
+		if l := path.Length(); !closeTo(l, test.expected, 1e-7) {
+			t.Errorf("Length %s: expected %v, actual %v\n", test.d, test.expected, l)
+		}
+	}
+
+	// compare a cubic with a fine polyline approximation
+	path, _ := utils.PathParser("M0 0 C30 80 70 -40 100 20")
+	segment := path.Contours()[0].Segments[0]
+	polyline := 0.0
+	prev := segment.PointAt(0)
+	for i := 1; i <= 100000; i++ {
+		p := segment.PointAt(float64(i) / 100000)
+		polyline += math.Hypot(p.X-prev.X, p.Y-prev.Y)
+		prev = p
+	}
+	if l := path.Length(); !closeTo(l, polyline, 1e-5) {
+		t.Errorf("Length: expected %v, actual %v\n", polyline, l)
+	}
+
+	lengths := path.SubpathLengths()
+	if len(lengths) != 1 || lengths[0] != path.Length() {
+		t.Errorf("SubpathLengths: expected %v, actual %v\n", []float64{path.Length()}, lengths)
+	}
+}
+
+func TestPointAtLength(t *testing.T) {
+	path, _ := utils.PathParser("M0 0 L10 0 L10 10 M20 20 A5 5 0 0 1 30 20")
+	var testCases = []struct {
+		length         float64
+		point, tangent utils.Point
+	}{
+		{-5, utils.Point{0, 0}, utils.Point{1, 0}},
+		{5, utils.Point{5, 0}, utils.Point{1, 0}},
+		{15, utils.Point{10, 5}, utils.Point{0, 1}},
+		{20 + 5*math.Pi/2, utils.Point{25, 15}, utils.Point{1, 0}},
+		{100, utils.Point{30, 20}, utils.Point{0, 1}},
+	}
+
+	for _, test := range testCases {
+		p := path.PointAtLength(test.length)
+		if !(closeTo(p.X, test.point.X, 1e-7) && closeTo(p.Y, test.point.Y, 1e-7)) {
+			t.Errorf("PointAtLength %v: expected %v, actual %v\n", test.length, test.point, p)
+		}
+		tangent := path.TangentAtLength(test.length)
+		if !(closeTo(tangent.X, test.tangent.X, 1e-7) && closeTo(tangent.Y, test.tangent.Y, 1e-7)) {
+			t.Errorf("TangentAtLength %v: expected %v, actual %v\n", test.length, test.tangent, tangent)
+		}
+	}
+}
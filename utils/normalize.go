@@ -0,0 +1,140 @@
+package utils
+
+import "strings"
+
+// ToAbsolute returns a copy of the path with all commands in absolute form.
+// Command types are preserved.
+func (p *Path) ToAbsolute() *Path {
+	var cur, start Point
+	path := &Path{}
+	for _, subpath := range p.Subpaths {
+		s := &Subpath{}
+		for _, c := range subpath.Commands {
+			symbol := strings.ToUpper(c.Symbol)
+			params := append([]float64{}, c.Params...)
+			if !c.IsAbsolute() {
+				switch symbol {
+				case "H":
+					params[0] += cur.X
+				case "V":
+					params[0] += cur.Y
+				case "A":
+					params[5] += cur.X
+					params[6] += cur.Y
+				default:
+					for i := 0; i+1 < len(params); i += 2 {
+						params[i] += cur.X
+						params[i+1] += cur.Y
+					}
+				}
+			}
+			switch symbol {
+			case "Z":
+				cur = start
+			case "H":
+				cur.X = params[0]
+			case "V":
+				cur.Y = params[0]
+			default:
+				cur = Point{params[len(params)-2], params[len(params)-1]}
+			}
+			if symbol == "M" {
+				start = cur
+			}
+			s.Commands = append(s.Commands, &Command{symbol, params})
+		}
+		path.Subpaths = append(path.Subpaths, s)
+	}
+	return path
+}
+
+// ToRelative returns a copy of the path with all commands in relative form.
+// Command types are preserved.
+func (p *Path) ToRelative() *Path {
+	var cur, start Point
+	path := &Path{}
+	for _, subpath := range p.ToAbsolute().Subpaths {
+		s := &Subpath{}
+		for _, c := range subpath.Commands {
+			params := relativeParams(c.Symbol, c.Params, cur)
+			switch c.Symbol {
+			case "Z":
+				cur = start
+			case "H":
+				cur.X = c.Params[0]
+			case "V":
+				cur.Y = c.Params[0]
+			default:
+				cur = Point{c.Params[len(c.Params)-2], c.Params[len(c.Params)-1]}
+			}
+			if c.Symbol == "M" {
+				start = cur
+			}
+			s.Commands = append(s.Commands, &Command{strings.ToLower(c.Symbol), params})
+		}
+		path.Subpaths = append(path.Subpaths, s)
+	}
+	return path
+}
+
+// reflect returns the reflection of control about the current point.
+func reflect(control, cur Point) Point {
+	return Point{2*cur.X - control.X, 2*cur.Y - control.Y}
+}
+
+// Normalize returns a copy of the path which only consists of absolute M, L,
+// C, Q, A and Z commands. Horizontal and vertical lines become lines, smooth
+// curves become curves with explicit reflected control points, and every
+// subpath starts with a moveto.
+func (p *Path) Normalize() *Path {
+	var cur, start, control Point
+	previous := ""
+	path := &Path{}
+	for _, subpath := range p.ToAbsolute().Subpaths {
+		s := &Subpath{}
+		for i, c := range subpath.Commands {
+			symbol, params := c.Symbol, c.Params
+			if i == 0 && symbol != "M" {
+				s.Commands = append(s.Commands, &Command{"M", []float64{cur.X, cur.Y}})
+				start = cur
+			}
+			switch symbol {
+			case "H":
+				symbol, params = "L", []float64{params[0], cur.Y}
+			case "V":
+				symbol, params = "L", []float64{cur.X, params[0]}
+			case "S":
+				c1 := cur
+				if previous == "C" {
+					c1 = reflect(control, cur)
+				}
+				symbol, params = "C", append([]float64{c1.X, c1.Y}, params...)
+			case "T":
+				c1 := cur
+				if previous == "Q" {
+					c1 = reflect(control, cur)
+				}
+				symbol, params = "Q", append([]float64{c1.X, c1.Y}, params...)
+			}
+
+			switch symbol {
+			case "Z":
+				cur = start
+			case "C":
+				control = Point{params[2], params[3]}
+			case "Q":
+				control = Point{params[0], params[1]}
+			}
+			if symbol != "Z" {
+				cur = Point{params[len(params)-2], params[len(params)-1]}
+			}
+			if symbol == "M" {
+				start = cur
+			}
+			previous = symbol
+			s.Commands = append(s.Commands, &Command{symbol, params})
+		}
+		path.Subpaths = append(path.Subpaths, s)
+	}
+	return path
+}
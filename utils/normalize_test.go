@@ -0,0 +1,66 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestToAbsolute(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		expected string
+	}{
+		{"m10 10 l5 5 h5 v5 z m1 1 l1 0", "M 10 10 L 15 15 H 20 V 20 Z M 11 11 L 12 11"},
+		{"M10 10 c1 1 2 2 3 3 s1 1 2 2 a5 5 0 0 1 5 5", "M 10 10 C 11 11 12 12 13 13 S 14 14 15 15 A 5 5 0 0 1 20 20"},
+		{"M10 10 q1 1 2 2 t2 2", "M 10 10 Q 11 11 12 12 T 14 14"},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.d)
+		expected, _ := utils.PathParser(test.expected)
+		if actual := path.ToAbsolute(); !expected.Compare(actual) {
+			t.Errorf("ToAbsolute: expected %v, actual %v\n", expected, actual)
+		}
+	}
+}
+
+func TestToRelative(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		expected string
+	}{
+		{"M10 10 L15 15 H20 V20 Z M11 11 L12 11", "m 10 10 l 5 5 h 5 v 5 z m 1 1 l 1 0"},
+		{"M10 10 A5 5 30 1 0 20 20", "m 10 10 a 5 5 30 1 0 10 10"},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.d)
+		expected, _ := utils.PathParser(test.expected)
+		if actual := path.ToRelative(); !expected.Compare(actual) {
+			t.Errorf("ToRelative: expected %v, actual %v\n", expected, actual)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		expected string
+	}{
+		{"M10 10 h5 v5 H10 Z", "M 10 10 L 15 10 L 15 15 L 10 15 Z"},
+		{"M0 0 C0 10 10 10 10 0 S20 -10 20 0", "M 0 0 C 0 10 10 10 10 0 C 10 -10 20 -10 20 0"},
+		{"M0 0 S10 10 20 0", "M 0 0 C 0 0 10 10 20 0"},
+		{"M0 0 Q5 10 10 0 T20 0 T30 0", "M 0 0 Q 5 10 10 0 Q 15 -10 20 0 Q 25 10 30 0"},
+		{"M10 10 L20 10 Z l5 5", "M 10 10 L 20 10 Z M 10 10 L 15 15"},
+		{"M10 10 L20 10 z m5 5 l1 0", "M 10 10 L 20 10 Z M 15 15 L 16 15"},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.d)
+		expected, _ := utils.PathParser(test.expected)
+		if actual := path.Normalize(); !expected.Compare(actual) {
+			t.Errorf("Normalize: expected %v, actual %v\n", expected, actual)
+		}
+	}
+}
@@ -1,39 +1,44 @@
 package utils
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
 
-// structure containing information about path commands as by specification
-var allCommands commands
-
-type commands struct {
-	all        []string
-	parameters map[string]int
-	start      string
-	end        string
-}
-
-func getCommands() commands {
-	var parameters = map[string]int{
-		"m": 2, "z": 0, "l": 2, "h": 1, "v": 1,
-		"c": 6, "s": 4, "q": 4, "t": 2, "a": 7,
+// parameters holds the number of parameters of every path command, indexed
+// by the lower case command letter. Letters which are no commands hold -1.
+// The table is never written after initialization, so parsing is safe for
+// concurrent use.
+var parameters = func() (table [128]int8) {
+	for i := range table {
+		table[i] = -1
 	}
-	var all []string
-	for k := range parameters {
-		all = append(all, k)
+	for c, n := range map[byte]int8{
+		'm': 2, 'z': 0, 'l': 2, 'h': 1, 'v': 1,
+		'c': 6, 's': 4, 'q': 4, 't': 2, 'a': 7,
+	} {
+		table[c] = n
 	}
-	return commands{all, parameters, "m", "z"}
-}
+	return table
+}()
 
-func (c *commands) isCommand(token string) bool {
-	for _, command := range c.all {
-		if strings.ToLower(token) == command {
-			return true
-		}
+// symbols holds the command letters as strings, so commands can be created
+// without allocating.
+var symbols = func() (table [128]string) {
+	for _, c := range "MmZzLlHhVvCcSsQqTtAa" {
+		table[c] = string(c)
+	}
+	return table
+}()
+
+// parameterCount returns the number of parameters of the command letter, or
+// -1 if c is no command.
+func parameterCount(c byte) int {
+	if c >= 128 {
+		return -1
 	}
-	return false
+	return int(parameters[c|0x20])
 }
 
 // PathParserError contains errors which have occured when parsing 'd'
@@ -46,12 +51,6 @@ func (err PathParserError) Error() string {
 	return err.msg
 }
 
-// token can contain an operator or an operand as string.
-type token struct {
-	value    string
-	operator bool
-}
-
 // Command is a representation of an SVG path command and its parameters.
 type Command struct {
 	Symbol string
@@ -113,117 +112,170 @@ func (p *Path) Compare(o *Path) bool {
 	return true
 }
 
-func reverse(ops []float64) []float64 {
-	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
-		ops[i], ops[j] = ops[j], ops[i]
-	}
-	return ops
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
 }
 
-func addOperand(tokens []token, operand string) ([]token, string) {
-	if operand != "" {
-		tokens = append(tokens, token{operand, false})
-		operand = ""
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// scanNumber returns the length of the number at the start of s according to
+// the SVG path grammar, or 0 if s does not start with a number.
+func scanNumber(s string) int {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+		digits++
 	}
-	return tokens, operand
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && isDigit(s[i]) {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for j < len(s) && isDigit(s[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
 }
 
-// tokenize takes value of 'd' attribute and transforms it to series of
-// operators and operands - step 1.
-func tokenize(raw string) []token {
+// PathParserFunc parses the value of a 'd' attribute according to the SVG
+// path grammar and passes every command to sink in order. Implicit commands
+// are made explicit, so subsequent pairs of a moveto are passed as lineto.
+// The params slice is reused between calls and only valid during the call.
+// Parsing stops at the first syntax error or error returned by sink, and that
+// error is returned; all commands before the error have been passed to sink.
+func PathParserFunc(raw string, sink func(symbol string, params []float64) error) error {
 	var (
-		tokens  []token
-		operand string
+		buf     [7]float64
+		letter  byte // command letter as written
+		symbol  string
+		n       int // number of parameters read for the current command
+		count   int // number of parameters of the current command
+		groups  int // number of complete commands since the command letter
+		numbers bool
 	)
-	for _, r := range raw {
-		char := string(r)
-		switch {
-		case allCommands.isCommand(char):
-			tokens, operand = addOperand(tokens, operand)
-			tokens = append(tokens, token{char, true})
-		case char == ".":
-			if operand == "" {
-				operand = "0"
+	incomplete := func() error {
+		if letter != 0 && (n != 0 || count > 0 && groups == 0) {
+			return PathParserError{"Incorrect number of parameters for " + symbols[letter]}
+		}
+		return nil
+	}
+
+	for i := 0; ; {
+		for i < len(raw) && isWhitespace(raw[i]) {
+			i++
+		}
+		if i < len(raw) && raw[i] == ',' {
+			// a comma may only separate two parameters
+			if !numbers {
+				return PathParserError{fmt.Sprintf("Unexpected comma at position %d", i)}
 			}
-			if strings.Contains(operand, char) {
-				tokens = append(tokens, token{operand, false})
-				operand = "0"
+			i++
+			for i < len(raw) && isWhitespace(raw[i]) {
+				i++
 			}
-			fallthrough
-		case char >= "0" && char <= "9" || char == "e":
-			operand += char
-		case char == "-":
-			if strings.HasSuffix(operand, "e") {
-				operand += char
-			} else {
-				tokens, operand = addOperand(tokens, operand)
-				operand = char
+			if i == len(raw) || parameterCount(raw[i]) >= 0 {
+				return PathParserError{fmt.Sprintf("Unexpected comma at position %d", i-1)}
 			}
-		default:
-			tokens, operand = addOperand(tokens, operand)
 		}
-	}
-	tokens, operand = addOperand(tokens, operand)
-	return tokens
-}
+		if i == len(raw) {
+			return incomplete()
+		}
 
-// toCommands takes a collection of operators and operands and produces
-// Command objects - step 2.
-func toCommands(tokens []token) ([]*Command, error) {
-	var (
-		commands []*Command
-		operands []float64
-	)
-	for i := len(tokens) - 1; i >= 0; i-- {
-		t := tokens[i]
-		if t.operator {
-			nParam := allCommands.parameters[strings.ToLower(t.value)]
-			nOperand := len(operands)
-			if nParam == 0 && nOperand == 0 {
-				command := &Command{Symbol: t.value}
-				commands = append([]*Command{command}, commands...)
-			} else if nParam != 0 && nOperand%nParam == 0 {
-				loopCount := nOperand / nParam
-				for i := 0; i < loopCount; i++ {
-					operator := t.value
-					if operator == "m" && i < loopCount-1 {
-						operator = "l"
-					}
-					if operator == "M" && i < loopCount-1 {
-						operator = "L"
-					}
-					command := &Command{operator, reverse(operands[:nParam])}
-					commands = append([]*Command{command}, commands...)
-					operands = operands[nParam:]
+		c := raw[i]
+		if parameterCount(c) >= 0 {
+			if err := incomplete(); err != nil {
+				return err
+			}
+			if letter == 0 && c|0x20 != 'm' {
+				return PathParserError{"Path data must start with a moveto command"}
+			}
+			letter, symbol, count, n, groups, numbers = c, symbols[c], parameterCount(c), 0, 0, false
+			i++
+			if count == 0 {
+				if err := sink(symbol, buf[:0]); err != nil {
+					return err
 				}
-			} else {
-				err := PathParserError{"Incorrect number of parameters for " + t.value}
-				return nil, err
 			}
+			continue
+		}
+		if letter == 0 {
+			return PathParserError{"Path data must start with a moveto command"}
+		}
+		if count == 0 {
+			return PathParserError{fmt.Sprintf("Unexpected parameter for closepath at position %d", i)}
+		}
+
+		if letter|0x20 == 'a' && (n == 3 || n == 4) {
+			// arc flags are single characters which need no separator
+			if c != '0' && c != '1' {
+				return PathParserError{fmt.Sprintf("Invalid arc flag at position %d", i)}
+			}
+			buf[n] = float64(c - '0')
+			i++
 		} else {
-			number, err := strconv.ParseFloat(t.value, 64)
+			length := scanNumber(raw[i:])
+			if length == 0 {
+				return PathParserError{fmt.Sprintf("Unexpected character %q at position %d", c, i)}
+			}
+			number, err := strconv.ParseFloat(raw[i:i+length], 64)
 			if err != nil {
-				return nil, err
+				return PathParserError{"Incorrect parameter " + raw[i:i+length] + " for " + symbols[letter]}
+			}
+			buf[n] = number
+			i += length
+		}
+		n++
+		numbers = true
+
+		if n == count {
+			if err := sink(symbol, buf[:count]); err != nil {
+				return err
+			}
+			n = 0
+			groups++
+			// subsequent pairs of a moveto are implicit lineto commands
+			if symbol == "M" {
+				symbol = "L"
+			} else if symbol == "m" {
+				symbol = "l"
 			}
-			operands = append(operands, number)
 		}
 	}
-	return commands, nil
 }
 
 // Create Subpaths takes a collection of Command objects and determines all
-// subpaths within the collection - step 3.
+// subpaths within the collection - step 2.
 func createSubpaths(commands []*Command) *Path {
 	path := &Path{}
 	var subpath []*Command
 	for i, command := range commands {
-		switch strings.ToLower(command.Symbol) {
-		case allCommands.start:
+		switch command.Symbol[0] | 0x20 {
+		case 'm':
 			if len(subpath) > 0 {
 				path.Subpaths = append(path.Subpaths, &Subpath{subpath})
 			}
 			subpath = []*Command{command}
-		case allCommands.end:
+		case 'z':
 			subpath = append(subpath, command)
 			path.Subpaths = append(path.Subpaths, &Subpath{subpath})
 			subpath = []*Command{}
@@ -237,13 +289,43 @@ func createSubpaths(commands []*Command) *Path {
 	return path
 }
 
+// parsePath parses the commands - step 1 - and groups them into subpaths. It
+// returns the path up to the first error together with the error. Commands
+// and parameters are allocated in blocks to keep allocations low.
+func parsePath(raw string) (*Path, error) {
+	var (
+		commands []*Command
+		block    []Command
+	)
+	params := make([]float64, 0, len(raw)/2)
+	err := PathParserFunc(raw, func(symbol string, p []float64) error {
+		if len(block) == 0 {
+			block = make([]Command, 32)
+		}
+		start := len(params)
+		params = append(params, p...)
+		c := &block[0]
+		block = block[1:]
+		c.Symbol, c.Params = symbol, params[start:len(params):len(params)]
+		commands = append(commands, c)
+		return nil
+	})
+	return createSubpaths(commands), err
+}
+
 // PathParser takes value of a 'd' attribute and transforms it to collection of
-// subpaths and commands.
+// subpaths and commands. It is safe for concurrent use.
 func PathParser(raw string) (*Path, error) {
-	allCommands = getCommands()
-	commands, err := toCommands(tokenize(raw))
+	path, err := parsePath(raw)
 	if err != nil {
 		return nil, err
 	}
-	return createSubpaths(commands), nil
+	return path, nil
+}
+
+// PathParserRecover works like PathParser, but in case of an error it returns
+// the path up to the first error together with the error. This matches the
+// error handling of renderers, which render path data up to the first error.
+func PathParserRecover(raw string) (*Path, error) {
+	return parsePath(raw)
 }
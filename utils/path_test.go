@@ -1,6 +1,9 @@
 package utils_test
 
 import (
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/chikamim/svgparser/utils"
@@ -206,3 +209,114 @@ func TestImplicitLineCommands(t *testing.T) {
 		}
 	}
 }
+
+func TestPathGrammar(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		expected string
+	}{
+		{"M0 0a1 1 0 00 1 1", "M 0 0 a 1 1 0 0 0 1 1"},
+		{"M0 0a1 1 0 1110 10", "M 0 0 a 1 1 0 1 1 10 10"},
+		{"M1e5 1E-5", "M 100000 0.00001"},
+		{"M+1,+2 L-.5.5", "M 1 2 L -0.5 0.5"},
+		{"M1.e2 2e+1", "M 100 20"},
+		{"m1 2 3 4 5 6", "m 1 2 l 3 4 l 5 6"},
+		{"M0 0 l1 1 2 2z", "M 0 0 l 1 1 l 2 2 z"},
+	}
+
+	for _, test := range testCases {
+		path, err := utils.PathParser(test.d)
+		expected, _ := utils.PathParser(test.expected)
+		if !(err == nil && expected.Compare(path)) {
+			t.Errorf("Path: expected %v, actual %v (%v)\n", expected, path, err)
+		}
+	}
+}
+
+func TestPathGrammarErrors(t *testing.T) {
+	for _, d := range []string{"L 10 10", "10 10", "M 10,,10", "M ,10 10", "M 10 10,", "M0 0 A1 1 0 2 1 5 5", "M0 0 Z 5", "M 1 x", "M1 2e"} {
+		if path, err := utils.PathParser(d); err == nil {
+			t.Errorf("Path: expected error for %q, actual %v\n", d, path)
+		}
+	}
+}
+
+func TestPathParserRecover(t *testing.T) {
+	var testCases = []struct {
+		d        string
+		expected string
+	}{
+		{"M 10 10 L 20 20 L 30 # 40 40", "M 10 10 L 20 20"},
+		{"M 10 10 20 20 30 Z", "M 10 10 L 20 20"},
+		{"M 10 10 L 20 20 Z 5", "M 10 10 L 20 20 Z"},
+	}
+
+	for _, test := range testCases {
+		path, err := utils.PathParserRecover(test.d)
+		expected, _ := utils.PathParser(test.expected)
+		if err == nil || !expected.Compare(path) {
+			t.Errorf("PathParserRecover: expected %v, actual %v (%v)\n", expected, path, err)
+		}
+	}
+}
+
+func TestPathParserConcurrent(t *testing.T) {
+	d := "M10 10 l5 5 h5 v5 c1 1 2 2 3 3 s1 1 2 2 q1 1 2 2 t2 2 a5 5 0 0 1 5 5 z"
+	expected, _ := utils.PathParser(d)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if path, err := utils.PathParser(d); err != nil || !expected.Compare(path) {
+					t.Errorf("Path: expected %v, actual %v\n", expected, path)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPathParserFunc(t *testing.T) {
+	var symbols string
+	var sum float64
+	err := utils.PathParserFunc("M0 0 1 1 2 2 Z m1 1 x", func(symbol string, params []float64) error {
+		symbols += symbol
+		for _, p := range params {
+			sum += p
+		}
+		return nil
+	})
+	if symbols != "MLLZm" || sum != 8 || err == nil {
+		t.Errorf("PathParserFunc: expected %v %v, actual %v %v (%v)\n", "MLLZm", 8, symbols, sum, err)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = utils.PathParserFunc("M0 0 L1 1 L2 2", func(symbol string, params []float64) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("PathParserFunc: expected sink error after %v call, actual %v after %v\n", 1, err, calls)
+	}
+}
+
+func BenchmarkPathParser(b *testing.B) {
+	d := strings.Repeat("M10 10 l5 5 h5 v5 c1 1 2 2 3 3 s1 1 2 2 q1 1 2 2 t2 2 a5 5 0 0 1 5 5 z ", 100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		utils.PathParser(d)
+	}
+}
+
+func BenchmarkPathParserFunc(b *testing.B) {
+	d := strings.Repeat("M10 10 l5 5 h5 v5 c1 1 2 2 3 3 s1 1 2 2 q1 1 2 2 t2 2 a5 5 0 0 1 5 5 z ", 100)
+	sink := func(symbol string, params []float64) error { return nil }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		utils.PathParserFunc(d, sink)
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import "math"
+
+// transformArc returns the radii, x-axis-rotation and sweep flag of an
+// elliptical arc after transformation.
+func transformArc(m Matrix, rx, ry, rotation, sweep float64) (float64, float64, float64, float64) {
+	// columns of the matrix mapping the unit circle onto the transformed ellipse
+	e := m.Multiply(Rotate(rotation)).Multiply(Scale(rx, ry))
+	p := e.A*e.A + e.C*e.C
+	q := e.A*e.B + e.C*e.D
+	r := e.B*e.B + e.D*e.D
+	mean, diff := (p+r)/2, math.Hypot((p-r)/2, q)
+	rx, ry = math.Sqrt(mean+diff), math.Sqrt(math.Max(mean-diff, 0))
+	rotation = math.Atan2(2*q, p-r) / 2 * 180 / math.Pi
+	if m.Determinant() < 0 {
+		sweep = 1 - sweep
+	}
+	return rx, ry, rotation, sweep
+}
+
+// Transform returns a copy of the path with the matrix applied to all
+// coordinates. All commands of the result are absolute, and horizontal and
+// vertical lines become lines.
+func (p *Path) Transform(m Matrix) *Path {
+	var cur, start Point
+	path := &Path{}
+	for _, subpath := range p.ToAbsolute().Subpaths {
+		s := &Subpath{}
+		for _, c := range subpath.Commands {
+			symbol, params := c.Symbol, c.Params
+			switch symbol {
+			case "H":
+				symbol, params = "L", []float64{params[0], cur.Y}
+			case "V":
+				symbol, params = "L", []float64{cur.X, params[0]}
+			}
+			out := make([]float64, len(params))
+			switch symbol {
+			case "Z":
+			case "A":
+				end := m.Apply(Point{params[5], params[6]})
+				if params[0] == 0 || params[1] == 0 {
+					symbol, out = "L", []float64{end.X, end.Y}
+					break
+				}
+				rx, ry, rotation, sweep := transformArc(m, math.Abs(params[0]), math.Abs(params[1]), params[2], params[4])
+				copy(out, []float64{rx, ry, rotation, params[3], sweep, end.X, end.Y})
+			default:
+				for i := 0; i+1 < len(params); i += 2 {
+					q := m.Apply(Point{params[i], params[i+1]})
+					out[i], out[i+1] = q.X, q.Y
+				}
+			}
+			switch {
+			case symbol == "Z":
+				cur = start
+			case symbol == "M":
+				cur = Point{params[0], params[1]}
+				start = cur
+			default:
+				cur = Point{params[len(params)-2], params[len(params)-1]}
+			}
+			s.Commands = append(s.Commands, &Command{symbol, out})
+		}
+		path.Subpaths = append(path.Subpaths, s)
+	}
+	return path
+}
@@ -0,0 +1,30 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestPathTransform(t *testing.T) {
+	var testCases = []struct {
+		d         string
+		transform string
+		expected  string
+	}{
+		{"M10 10 l10 0 v10 h-10 z m5 5 l1 1", "translate(1 2)", "M 11 12 L 21 12 L 21 22 L 11 22 Z M 16 17 L 17 18"},
+		{"M0 0 C1 1 2 2 3 3 S5 5 6 6", "scale(2)", "M 0 0 C 2 2 4 4 6 6 S 10 10 12 12"},
+		{"M0 0 A10 5 0 0 1 20 0", "scale(1 2)", "M 0 0 A 10 10 0 0 1 20 0"},
+		{"M0 0 A10 5 0 0 1 20 0", "scale(-1 1)", "M 0 0 A 10 5 0 0 0 -20 0"},
+		{"M0 0 A10 5 0 0 1 20 0", "rotate(90)", "M 0 0 A 10 5 90 0 1 0 20"},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.d)
+		m, _ := utils.TransformParser(test.transform)
+		expected, _ := utils.PathParser(test.expected)
+		if actual := path.Transform(m); !closePaths(expected, actual) {
+			t.Errorf("Transform: expected %v, actual %v\n", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package utils
+
+import "math"
+
+// Segment is a single drawing segment of a path in absolute coordinates.
+// Symbol is one of "L", "Q", "C" or "A". Points holds the start point, the
+// control points and the end point, i.e. two points for lines and arcs, three
+// for quadratic and four for cubic Bézier curves. Arcs additionally hold
+// their center parameterization.
+type Segment struct {
+	Symbol string
+	Points []Point
+	Arc    Arc
+}
+
+// Contour is a subpath resolved into segments. A closed contour ends with a
+// line back to its start point unless it already ends there.
+type Contour struct {
+	Start    Point
+	Segments []Segment
+	Closed   bool
+}
+
+// Box is an axis-aligned bounding box. An empty box has Min greater than Max.
+type Box struct {
+	Min, Max Point
+}
+
+// EmptyBox returns a box which contains nothing.
+func EmptyBox() Box {
+	return Box{Point{math.Inf(1), math.Inf(1)}, Point{math.Inf(-1), math.Inf(-1)}}
+}
+
+// IsEmpty returns true if the box contains nothing.
+func (b Box) IsEmpty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y
+}
+
+// Width returns the width of the box.
+func (b Box) Width() float64 {
+	if b.IsEmpty() {
+		return 0
+	}
+	return b.Max.X - b.Min.X
+}
+
+// Height returns the height of the box.
+func (b Box) Height() float64 {
+	if b.IsEmpty() {
+		return 0
+	}
+	return b.Max.Y - b.Min.Y
+}
+
+// Extend returns the box grown to contain the point.
+func (b Box) Extend(p Point) Box {
+	return Box{
+		Point{math.Min(b.Min.X, p.X), math.Min(b.Min.Y, p.Y)},
+		Point{math.Max(b.Max.X, p.X), math.Max(b.Max.Y, p.Y)},
+	}
+}
+
+// Union returns the smallest box containing both boxes.
+func (b Box) Union(o Box) Box {
+	if o.IsEmpty() {
+		return b
+	}
+	return b.Extend(o.Min).Extend(o.Max)
+}
+
+// Transform returns the bounding box of the box transformed by m.
+func (b Box) Transform(m Matrix) Box {
+	if b.IsEmpty() {
+		return b
+	}
+	r := EmptyBox()
+	for _, p := range []Point{b.Min, {b.Max.X, b.Min.Y}, b.Max, {b.Min.X, b.Max.Y}} {
+		r = r.Extend(m.Apply(p))
+	}
+	return r
+}
+
+func lerp(a, b Point, t float64) Point {
+	return Point{a.X + (b.X-a.X)*t, a.Y + (b.Y-a.Y)*t}
+}
+
+// Start returns the start point of the segment.
+func (s Segment) Start() Point {
+	return s.Points[0]
+}
+
+// End returns the end point of the segment.
+func (s Segment) End() Point {
+	return s.Points[len(s.Points)-1]
+}
+
+// PointAt returns the point at parameter t in [0, 1] along the segment.
+func (s Segment) PointAt(t float64) Point {
+	if s.Symbol == "A" {
+		if t == 1 {
+			return s.End()
+		}
+		return s.Arc.PointAt(t)
+	}
+	// de Casteljau
+	points := append([]Point{}, s.Points...)
+	for n := len(points) - 1; n > 0; n-- {
+		for i := 0; i < n; i++ {
+			points[i] = lerp(points[i], points[i+1], t)
+		}
+	}
+	return points[0]
+}
+
+// Derivative returns the derivative of the segment with respect to t at
+// parameter t in [0, 1].
+func (s Segment) Derivative(t float64) Point {
+	p := s.Points
+	mt := 1 - t
+	switch s.Symbol {
+	case "A":
+		return s.Arc.Derivative(t)
+	case "Q":
+		return Point{
+			2*mt*(p[1].X-p[0].X) + 2*t*(p[2].X-p[1].X),
+			2*mt*(p[1].Y-p[0].Y) + 2*t*(p[2].Y-p[1].Y),
+		}
+	case "C":
+		return Point{
+			3*mt*mt*(p[1].X-p[0].X) + 6*mt*t*(p[2].X-p[1].X) + 3*t*t*(p[3].X-p[2].X),
+			3*mt*mt*(p[1].Y-p[0].Y) + 6*mt*t*(p[2].Y-p[1].Y) + 3*t*t*(p[3].Y-p[2].Y),
+		}
+	}
+	return Point{p[1].X - p[0].X, p[1].Y - p[0].Y}
+}
+
+// quadraticRoots returns the real roots of a*t^2 + b*t + c within (0, 1).
+func quadraticRoots(a, b, c float64) []float64 {
+	var roots []float64
+	if math.Abs(a) < 1e-12 {
+		if math.Abs(b) > 1e-12 {
+			roots = append(roots, -c/b)
+		}
+	} else if d := b*b - 4*a*c; d >= 0 {
+		sq := math.Sqrt(d)
+		roots = append(roots, (-b+sq)/(2*a), (-b-sq)/(2*a))
+	}
+	var inside []float64
+	for _, r := range roots {
+		if r > 0 && r < 1 {
+			inside = append(inside, r)
+		}
+	}
+	return inside
+}
+
+// arcParameter returns the parameter t of the angle theta in radians on the
+// arc, and whether the angle lies on the arc.
+func (a Arc) arcParameter(theta float64) (float64, bool) {
+	start, sweep := a.Start*math.Pi/180, a.Sweep*math.Pi/180
+	delta := math.Mod(theta-start, 2*math.Pi)
+	if sweep > 0 && delta < 0 {
+		delta += 2 * math.Pi
+	} else if sweep < 0 && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+	t := delta / sweep
+	return t, t >= 0 && t <= 1
+}
+
+// extrema returns the parameters at which the segment has a horizontal or
+// vertical tangent.
+func (s Segment) extrema() []float64 {
+	p := s.Points
+	var ts []float64
+	switch s.Symbol {
+	case "Q":
+		for _, d := range [][3]float64{{p[0].X, p[1].X, p[2].X}, {p[0].Y, p[1].Y, p[2].Y}} {
+			if den := d[0] - 2*d[1] + d[2]; den != 0 {
+				if t := (d[0] - d[1]) / den; t > 0 && t < 1 {
+					ts = append(ts, t)
+				}
+			}
+		}
+	case "C":
+		for _, d := range [][4]float64{{p[0].X, p[1].X, p[2].X, p[3].X}, {p[0].Y, p[1].Y, p[2].Y, p[3].Y}} {
+			a := -d[0] + 3*d[1] - 3*d[2] + d[3]
+			b := 2 * (d[0] - 2*d[1] + d[2])
+			c := d[1] - d[0]
+			ts = append(ts, quadraticRoots(a, b, c)...)
+		}
+	case "A":
+		sin, cos := math.Sincos(s.Arc.Rotation * math.Pi / 180)
+		thetaX := math.Atan2(-s.Arc.RY*sin, s.Arc.RX*cos)
+		thetaY := math.Atan2(s.Arc.RY*cos, s.Arc.RX*sin)
+		for _, theta := range []float64{thetaX, thetaX + math.Pi, thetaY, thetaY + math.Pi} {
+			if t, ok := s.Arc.arcParameter(theta); ok {
+				ts = append(ts, t)
+			}
+		}
+	}
+	return ts
+}
+
+// Bounds returns the exact bounding box of the segment, taking the extrema
+// of curves into account rather than their control points.
+func (s Segment) Bounds() Box {
+	b := EmptyBox().Extend(s.Start()).Extend(s.End())
+	for _, t := range s.extrema() {
+		b = b.Extend(s.PointAt(t))
+	}
+	return b
+}
+
+// Contours resolves the path into contours, one for every subpath. Relative,
+// shorthand and smooth commands are resolved, degenerate arcs become lines
+// and arcs with equal end points are omitted.
+func (p *Path) Contours() []Contour {
+	var contours []Contour
+	var cur Point
+	for _, subpath := range p.Normalize().Subpaths {
+		contour := Contour{}
+		for _, c := range subpath.Commands {
+			params := c.Params
+			var end Point
+			if len(params) >= 2 {
+				end = Point{params[len(params)-2], params[len(params)-1]}
+			}
+			switch c.Symbol {
+			case "M":
+				contour.Start = end
+			case "L":
+				contour.Segments = append(contour.Segments, Segment{"L", []Point{cur, end}, Arc{}})
+			case "Q":
+				contour.Segments = append(contour.Segments, Segment{"Q", []Point{cur, {params[0], params[1]}, end}, Arc{}})
+			case "C":
+				contour.Segments = append(contour.Segments,
+					Segment{"C", []Point{cur, {params[0], params[1]}, {params[2], params[3]}, end}, Arc{}})
+			case "A":
+				arc, ok := EndpointToCenter(cur, params[0], params[1], params[2], params[3] != 0, params[4] != 0, end)
+				if ok {
+					contour.Segments = append(contour.Segments, Segment{"A", []Point{cur, end}, arc})
+				} else if cur != end {
+					contour.Segments = append(contour.Segments, Segment{"L", []Point{cur, end}, Arc{}})
+				}
+			case "Z":
+				if cur != contour.Start {
+					contour.Segments = append(contour.Segments, Segment{"L", []Point{cur, contour.Start}, Arc{}})
+				}
+				contour.Closed = true
+				end = contour.Start
+			}
+			cur = end
+		}
+		contours = append(contours, contour)
+	}
+	return contours
+}
+
+// Split splits the segment at parameter t in [0, 1] into two segments which
+// together have the same shape.
+func (s Segment) Split(t float64) (Segment, Segment) {
+	if s.Symbol == "A" {
+		mid := s.PointAt(t)
+		first, second := s.Arc, s.Arc
+		first.Sweep = s.Arc.Sweep * t
+		second.Start = s.Arc.Start + first.Sweep
+		second.Sweep = s.Arc.Sweep - first.Sweep
+		return Segment{"A", []Point{s.Start(), mid}, first}, Segment{"A", []Point{mid, s.End()}, second}
+	}
+	// de Casteljau: the first points of every level form the first half and
+	// the last points in reverse order the second half
+	n := len(s.Points)
+	points := append([]Point{}, s.Points...)
+	first, second := make([]Point, n), make([]Point, n)
+	for level := 0; level < n; level++ {
+		first[level] = points[0]
+		second[n-1-level] = points[n-1-level]
+		for i := 0; i < n-1-level; i++ {
+			points[i] = lerp(points[i], points[i+1], t)
+		}
+	}
+	return Segment{s.Symbol, first, Arc{}}, Segment{s.Symbol, second, Arc{}}
+}
+
+// Command returns the absolute drawing command of the segment. Its start
+// point is implied by the preceding command.
+func (s Segment) Command() *Command {
+	end := s.End()
+	switch s.Symbol {
+	case "A":
+		largeArc, sweep := 0.0, 0.0
+		if math.Abs(s.Arc.Sweep) > 180 {
+			largeArc = 1
+		}
+		if s.Arc.Sweep > 0 {
+			sweep = 1
+		}
+		return &Command{"A", []float64{s.Arc.RX, s.Arc.RY, s.Arc.Rotation, largeArc, sweep, end.X, end.Y}}
+	case "Q", "C":
+		var params []float64
+		for _, p := range s.Points[1:] {
+			params = append(params, p.X, p.Y)
+		}
+		return &Command{s.Symbol, params}
+	}
+	return &Command{"L", []float64{end.X, end.Y}}
+}
+
+// PathFromContours builds a path of absolute commands from contours. A closing
+// line of a closed contour is left to the closepath command.
+func PathFromContours(contours []Contour) *Path {
+	path := &Path{}
+	for _, c := range contours {
+		segments := c.Segments
+		if n := len(segments); c.Closed && n > 1 && segments[n-1].Symbol == "L" && segments[n-1].End() == c.Start {
+			segments = segments[:n-1]
+		}
+		s := &Subpath{Commands: []*Command{{"M", []float64{c.Start.X, c.Start.Y}}}}
+		for _, segment := range segments {
+			s.Commands = append(s.Commands, segment.Command())
+		}
+		if c.Closed {
+			s.Commands = append(s.Commands, &Command{"Z", []float64{}})
+		}
+		path.Subpaths = append(path.Subpaths, s)
+	}
+	return path
+}
@@ -0,0 +1,300 @@
+package utils
+
+import "math"
+
+// cornerAngle is the turning angle in radians above which a polyline vertex
+// is kept as a corner when fitting curves.
+const cornerAngle = math.Pi / 3
+
+// distanceToSegment returns the distance of p to the line segment from a to b.
+func distanceToSegment(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+	}
+	return math.Hypot(a.X+t*dx-p.X, a.Y+t*dy-p.Y)
+}
+
+// SimplifyPolyline removes points from the polyline using the
+// Ramer-Douglas-Peucker algorithm, such that no removed point is farther than
+// tolerance from the result. The first and last points are always kept.
+func SimplifyPolyline(points []Point, tolerance float64) []Point {
+	if len(points) < 3 {
+		return append([]Point{}, points...)
+	}
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		index, max := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			// distances to the segment rather than the line keep points where
+			// the polyline turns back
+			if d := distanceToSegment(points[i], points[first], points[last]); d > max {
+				index, max = i, d
+			}
+		}
+		if index >= 0 {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+	var simplified []Point
+	for i, p := range points {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// isDegenerate returns true if all points of the segment are within
+// tolerance of its start point.
+func (s Segment) isDegenerate(tolerance float64) bool {
+	for _, p := range s.Points[1:] {
+		if math.Hypot(p.X-s.Start().X, p.Y-s.Start().Y) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// Simplify returns a copy of the path in absolute commands with fewer
+// segments. Segments of zero length are dropped, curves which are straight
+// within tolerance become lines and runs of lines are simplified with the
+// Ramer-Douglas-Peucker algorithm, which merges collinear lines.
+func (p *Path) Simplify(tolerance float64) *Path {
+	contours := p.Contours()
+	for i, c := range contours {
+		var segments []Segment
+		run := []Point{c.Start}
+		flush := func() {
+			simplified := SimplifyPolyline(run, tolerance)
+			for j := 1; j < len(simplified); j++ {
+				segments = append(segments, Segment{"L", []Point{simplified[j-1], simplified[j]}, Arc{}})
+			}
+			run = run[len(run)-1:]
+		}
+		for _, s := range c.Segments {
+			if s.isDegenerate(tolerance) && s.End() == s.Start() {
+				continue
+			}
+			if s.Symbol != "L" && !s.isFlat(tolerance) {
+				flush()
+				s.Points[0] = run[0]
+				segments = append(segments, s)
+				run = []Point{s.End()}
+				continue
+			}
+			run = append(run, s.End())
+		}
+		flush()
+		contours[i].Segments = segments
+	}
+	return PathFromContours(contours)
+}
+
+func normalized(p Point) Point {
+	l := math.Hypot(p.X, p.Y)
+	if l == 0 {
+		return p
+	}
+	return Point{p.X / l, p.Y / l}
+}
+
+func dot(a, b Point) float64 {
+	return a.X*b.X + a.Y*b.Y
+}
+
+func sub(a, b Point) Point {
+	return Point{a.X - b.X, a.Y - b.Y}
+}
+
+func scaled(p Point, f float64) Point {
+	return Point{p.X * f, p.Y * f}
+}
+
+func add(a, b Point) Point {
+	return Point{a.X + b.X, a.Y + b.Y}
+}
+
+// chordParameters assigns parameters in [0, 1] to the points proportional
+// to the length of the polyline up to them.
+func chordParameters(points []Point) []float64 {
+	u := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		u[i] = u[i-1] + math.Hypot(points[i].X-points[i-1].X, points[i].Y-points[i-1].Y)
+	}
+	for i := range u {
+		if total := u[len(u)-1]; total > 0 {
+			u[i] /= total
+		}
+	}
+	return u
+}
+
+// fitBezier computes the least squares cubic with the given end tangents.
+func fitBezier(points []Point, u []float64, left, right Point) Segment {
+	first, last := points[0], points[len(points)-1]
+	var c00, c01, c11, x0, x1 float64
+	for i, t := range u {
+		mt := 1 - t
+		a1 := scaled(left, 3*mt*mt*t)
+		a2 := scaled(right, 3*mt*t*t)
+		c00 += dot(a1, a1)
+		c01 += dot(a1, a2)
+		c11 += dot(a2, a2)
+		base := add(scaled(first, mt*mt*mt+3*mt*mt*t), scaled(last, 3*mt*t*t+t*t*t))
+		tmp := sub(points[i], base)
+		x0 += dot(a1, tmp)
+		x1 += dot(a2, tmp)
+	}
+	det := c00*c11 - c01*c01
+	alpha1, alpha2 := 0.0, 0.0
+	if math.Abs(det) > 1e-12 {
+		alpha1 = (x0*c11 - x1*c01) / det
+		alpha2 = (c00*x1 - c01*x0) / det
+	}
+	// fall back to the Wu-Barsky heuristic for degenerate solutions
+	length := math.Hypot(last.X-first.X, last.Y-first.Y)
+	if alpha1 < 1e-6*length || alpha2 < 1e-6*length {
+		alpha1, alpha2 = length/3, length/3
+	}
+	return Segment{"C", []Point{first, add(first, scaled(left, alpha1)), add(last, scaled(right, alpha2)), last}, Arc{}}
+}
+
+// maxError returns the largest squared distance of the points to the curve
+// at their parameters and the index of that point.
+func maxError(points []Point, u []float64, s Segment) (float64, int) {
+	max, index := 0.0, len(points)/2
+	for i := 1; i < len(points)-1; i++ {
+		d := sub(s.PointAt(u[i]), points[i])
+		if e := dot(d, d); e >= max {
+			max, index = e, i
+		}
+	}
+	return max, index
+}
+
+// reparameterize improves the parameters of the points with a Newton step
+// towards the nearest point on the curve.
+func reparameterize(points []Point, u []float64, s Segment) {
+	p := s.Points
+	for i, t := range u {
+		d := sub(s.PointAt(t), points[i])
+		d1 := s.Derivative(t)
+		d2 := add(scaled(add(sub(p[2], scaled(p[1], 2)), p[0]), 6*(1-t)), scaled(add(sub(p[3], scaled(p[2], 2)), p[1]), 6*t))
+		den := dot(d1, d1) + dot(d, d2)
+		if den != 0 {
+			u[i] = math.Max(0, math.Min(1, t-dot(d, d1)/den))
+		}
+	}
+}
+
+// fitCubic fits cubics to the points with the Schneider algorithm.
+func fitCubic(points []Point, left, right Point, tolerance float64, segments []Segment) []Segment {
+	if len(points) == 2 {
+		d := math.Hypot(points[1].X-points[0].X, points[1].Y-points[0].Y) / 3
+		return append(segments, Segment{"C", []Point{
+			points[0], add(points[0], scaled(left, d)), add(points[1], scaled(right, d)), points[1],
+		}, Arc{}})
+	}
+
+	u := chordParameters(points)
+	s := fitBezier(points, u, left, right)
+	errorSq, split := maxError(points, u, s)
+	if errorSq <= tolerance*tolerance {
+		return append(segments, s)
+	}
+	if errorSq <= 4*tolerance*tolerance {
+		for i := 0; i < 20; i++ {
+			reparameterize(points, u, s)
+			s = fitBezier(points, u, left, right)
+			if errorSq, split = maxError(points, u, s); errorSq <= tolerance*tolerance {
+				return append(segments, s)
+			}
+		}
+	}
+
+	center := normalized(sub(points[split-1], points[split+1]))
+	if center == (Point{}) {
+		center = normalized(sub(points[split-1], points[split]))
+	}
+	segments = fitCubic(points[:split+1], left, center, tolerance, segments)
+	return fitCubic(points[split:], scaled(center, -1), right, tolerance, segments)
+}
+
+// FitCubics fits cubic Bézier curves to a dense polyline using the Schneider
+// algorithm, such that every point is within tolerance of the curves. Vertices
+// where the polyline turns sharply are kept as corners.
+func FitCubics(points []Point, tolerance float64) []Segment {
+	// drop repeated points, they have no tangent
+	var unique []Point
+	for i, p := range points {
+		if i == 0 || p != points[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 2 {
+		return nil
+	}
+
+	var segments []Segment
+	first := 0
+	for i := 1; i < len(unique); i++ {
+		if i < len(unique)-1 {
+			in := normalized(sub(unique[i], unique[i-1]))
+			out := normalized(sub(unique[i+1], unique[i]))
+			if math.Acos(math.Max(-1, math.Min(1, dot(in, out)))) <= cornerAngle {
+				continue
+			}
+		}
+		run := unique[first : i+1]
+		left := normalized(sub(run[1], run[0]))
+		right := normalized(sub(run[len(run)-2], run[len(run)-1]))
+		segments = fitCubic(run, left, right, tolerance, segments)
+		first = i
+	}
+	return segments
+}
+
+// FitCurves returns a copy of the path in absolute commands in which runs of
+// lines are replaced by cubic Bézier curves fitted within tolerance. Fitted
+// curves which are straight become lines again.
+func (p *Path) FitCurves(tolerance float64) *Path {
+	contours := p.Contours()
+	for i, c := range contours {
+		var segments []Segment
+		var run []Point
+		flush := func() {
+			if len(run) >= 3 {
+				for _, s := range FitCubics(run, tolerance) {
+					if s.isFlat(epsilon) {
+						s = Segment{"L", []Point{s.Start(), s.End()}, Arc{}}
+					}
+					segments = append(segments, s)
+				}
+			} else if len(run) == 2 {
+				segments = append(segments, Segment{"L", run, Arc{}})
+			}
+			run = nil
+		}
+		for _, s := range c.Segments {
+			if s.Symbol != "L" {
+				flush()
+				segments = append(segments, s)
+				continue
+			}
+			if len(run) == 0 {
+				run = append(run, s.Start())
+			}
+			run = append(run, s.End())
+		}
+		flush()
+		contours[i].Segments = segments
+	}
+	return PathFromContours(contours)
+}
@@ -0,0 +1,140 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestSimplifyPolyline(t *testing.T) {
+	testCases := []struct {
+		points    []utils.Point
+		tolerance float64
+		expected  []utils.Point
+	}{
+		{[]utils.Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}, 0, []utils.Point{{0, 0}, {3, 0}}},
+		{[]utils.Point{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 0}}, 0.2, []utils.Point{{0, 0}, {3, 0}}},
+		{[]utils.Point{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 0}}, 0.05, []utils.Point{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 0}}},
+		{[]utils.Point{{0, 0}, {5, 5}, {10, 0}}, 1, []utils.Point{{0, 0}, {5, 5}, {10, 0}}},
+		// turning back on the same line is kept
+		{[]utils.Point{{0, 0}, {10, 0}, {5, 0}}, 0, []utils.Point{{0, 0}, {10, 0}, {5, 0}}},
+	}
+
+	for _, test := range testCases {
+		actual := utils.SimplifyPolyline(test.points, test.tolerance)
+		if !closePoints(actual, test.expected) {
+			t.Errorf("SimplifyPolyline: expected %v, actual %v\n", test.expected, actual)
+		}
+	}
+}
+
+func closePoints(a, b []utils.Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !closeTo(a[i].X, b[i].X, 1e-9) || !closeTo(a[i].Y, b[i].Y, 1e-9) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPathSimplify(t *testing.T) {
+	testCases := []struct {
+		path      string
+		tolerance float64
+		expected  string
+	}{
+		{"M0 0 L10 0 L20 0 L20 10", 0, "M 0 0 L 20 0 L 20 10"},
+		{"M0 0 L10 0 L10 0 L20 0", 0, "M 0 0 L 20 0"},
+		{"M0 0 L10 0 L20 0 L20 20 L0 20 Z", 0, "M 0 0 L 20 0 L 20 20 L 0 20 Z"},
+		{"M0 0 C3 0 7 0 10 0 L20 0", 0, "M 0 0 L 20 0"},
+		{"M0 0 C0 10 10 10 10 0 L10 0 L20 0", 0, "M 0 0 C 0 10 10 10 10 0 L 20 0"},
+		{"M0 0 L1 0.1 L2 0 L3 0.1 L4 0", 0.5, "M 0 0 L 4 0"},
+		{"M0 0 Q5 0 10 0 Q5 0 0 0", 0, "M 0 0 L 10 0 L 0 0"},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.path)
+		actual := path.Simplify(test.tolerance).String()
+		if actual != test.expected {
+			t.Errorf("Simplify %q: expected %v, actual %v\n", test.path, test.expected, actual)
+		}
+	}
+}
+
+func TestFitCubics(t *testing.T) {
+	var points []utils.Point
+	for i := 0; i <= 200; i++ {
+		a := math.Pi * float64(i) / 200
+		points = append(points, utils.Point{X: 100 * math.Cos(a), Y: 100 * math.Sin(a)})
+	}
+	// a sharp corner
+	points = append(points, utils.Point{X: -100, Y: -50})
+
+	for _, tolerance := range []float64{1, 0.1} {
+		segments := utils.FitCubics(points, tolerance)
+		if len(segments) < 2 || len(segments) > 20 {
+			t.Errorf("FitCubics: unexpected number of curves %v\n", len(segments))
+		}
+		if segments[0].Start() != points[0] || segments[len(segments)-1].End() != points[len(points)-1] {
+			t.Errorf("FitCubics: curves should start and end at the polyline ends\n")
+		}
+		polyline := utils.Polyline{}
+		for _, s := range segments {
+			polyline.Points = append(polyline.Points, s.Flatten(tolerance/100)...)
+		}
+		for _, p := range points {
+			if d := distanceToPolyline(p, polyline); d > tolerance*1.01 {
+				t.Errorf("FitCubics: point %v deviates %v more than %v\n", p, d, tolerance)
+			}
+		}
+	}
+}
+
+func TestPathFitCurves(t *testing.T) {
+	dense := &utils.Path{}
+	subpath := &utils.Subpath{Commands: []*utils.Command{{Symbol: "M", Params: []float64{100, 0}}}}
+	for i := 1; i < 360; i++ {
+		a := 2 * math.Pi * float64(i) / 360
+		subpath.Commands = append(subpath.Commands, &utils.Command{Symbol: "L", Params: []float64{100 * math.Cos(a), 100 * math.Sin(a)}})
+	}
+	subpath.Commands = append(subpath.Commands, &utils.Command{Symbol: "Z", Params: []float64{}})
+	dense.Subpaths = append(dense.Subpaths, subpath)
+
+	fitted := dense.FitCurves(0.1)
+	commands := fitted.Subpaths[0].Commands
+	if len(commands) > 20 || commands[len(commands)-1].Symbol != "Z" {
+		t.Errorf("FitCurves: expected few curves and a closepath, actual %v\n", fitted)
+	}
+	if d := math.Abs(fitted.Length() - dense.Length()); d > 0.5 {
+		t.Errorf("FitCurves: length differs by %v\n", d)
+	}
+
+	square, _ := utils.PathParser("M0 0 L10 0 L10 10 L0 10 Z")
+	if actual := square.FitCurves(0.1).String(); actual != "M 0 0 L 10 0 L 10 10 L 0 10 Z" {
+		t.Errorf("FitCurves: expected corners to stay lines, actual %v\n", actual)
+	}
+}
+
+func TestPathFromContours(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"m10 10 h10 v10 z", "M 10 10 L 20 10 L 20 20 Z"},
+		{"M0 0 A10 10 0 1 1 20 0 Q30 10 40 0 C40 10 50 10 50 0", "M 0 0 A 10 10 0 0 1 20 0 Q 30 10 40 0 C 40 10 50 10 50 0"},
+		{"M0 0 A10 10 0 1 0 10 10", "M 0 0 A 10 10 0 1 0 10 10"},
+		{"M0 0 L10 0 M20 0 L30 0 L20 0 Z", "M 0 0 L 10 0 M 20 0 L 30 0 Z"},
+	}
+
+	for _, test := range testCases {
+		path, _ := utils.PathParser(test.path)
+		actual := utils.PathFromContours(path.Contours()).String()
+		if actual != test.expected {
+			t.Errorf("PathFromContours %q: expected %v, actual %v\n", test.path, test.expected, actual)
+		}
+	}
+}
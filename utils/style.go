@@ -1,11 +1,13 @@
 package utils
 
-import "regexp"
+import "strings"
 
-// Style represents a CSS style property and its value.
+// Style represents a CSS style property, its value and whether it is
+// marked !important.
 type Style struct {
-	Property string
-	Value    string
+	Property  string
+	Value     string
+	Important bool
 }
 
 // Styles is a collection of Style objects.
@@ -18,20 +20,146 @@ func (ss Styles) Compare(o Styles) bool {
 	}
 
 	for i, s := range ss {
-		if !(s.Property == o[i].Property && s.Value == o[i].Value) {
+		if *s != *o[i] {
 			return false
 		}
 	}
 	return true
 }
 
+// String returns the styles as value of a style attribute.
+func (ss Styles) String() string {
+	values := make([]string, len(ss))
+	for i, s := range ss {
+		values[i] = s.Property + ":" + s.Value
+		if s.Important {
+			values[i] += " !important"
+		}
+	}
+	return strings.Join(values, ";")
+}
+
 // StyleParser takes value of a style attribute and converts it to
-// Style objects.
+// Style objects, following CSS Syntax Level 3. Values may contain colons,
+// strings, functions and escapes, and !important is recognized. Property
+// names are lower case except for custom properties. Comments are dropped,
+// and invalid declarations and at-rules are skipped.
 func StyleParser(raw string) Styles {
-	var styles Styles
-	regex := regexp.MustCompile(`([a-zA-z-]+):([^:;]+);?`)
-	for _, s := range regex.FindAllStringSubmatch(raw, -1) {
-		styles = append(styles, &Style{s[1], s[2]})
+	return parseDeclarations(CSSTokenizer(raw))
+}
+
+// nextSemicolon returns the index of the next semicolon at or after i which
+// is not nested in a block or function, or the number of tokens.
+func nextSemicolon(tokens []CSSToken, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case FunctionToken, OpenParenToken, OpenSquareToken, OpenCurlyToken:
+			depth++
+		case CloseParenToken, CloseSquareToken, CloseCurlyToken:
+			if depth > 0 {
+				depth--
+			}
+		case SemicolonToken:
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// skipAtRule returns the index after the at-rule starting at i, which ends
+// with a semicolon or a block.
+func skipAtRule(tokens []CSSToken, i int) int {
+	depth := 0
+	for i++; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case FunctionToken, OpenParenToken, OpenSquareToken, OpenCurlyToken:
+			depth++
+		case CloseParenToken, CloseSquareToken:
+			if depth > 0 {
+				depth--
+			}
+		case CloseCurlyToken:
+			if depth > 0 {
+				depth--
+			}
+			if depth == 0 {
+				return i + 1
+			}
+		case SemicolonToken:
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// trimWhitespace returns the tokens without leading and trailing whitespace.
+func trimWhitespace(tokens []CSSToken) []CSSToken {
+	for len(tokens) > 0 && tokens[0].Type == WhitespaceToken {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].Type == WhitespaceToken {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// serialize returns the source text of the tokens.
+func serialize(tokens []CSSToken) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(t.Raw)
+	}
+	return b.String()
+}
+
+// parseDeclarations parses the tokens of a list of declarations.
+func parseDeclarations(tokens []CSSToken) Styles {
+	var declarations Styles
+	for i := 0; i < len(tokens); {
+		switch tokens[i].Type {
+		case WhitespaceToken, SemicolonToken:
+			i++
+		case AtKeywordToken:
+			i = skipAtRule(tokens, i)
+		case IdentToken:
+			end := nextSemicolon(tokens, i)
+			if d, ok := parseDeclaration(tokens[i:end]); ok {
+				declarations = append(declarations, d)
+			}
+			i = end
+		default:
+			i = nextSemicolon(tokens, i)
+		}
+	}
+	return declarations
+}
+
+// parseDeclaration parses the tokens of a declaration, which start with the
+// property name.
+func parseDeclaration(tokens []CSSToken) (*Style, bool) {
+	d := &Style{Property: tokens[0].Value}
+	if !strings.HasPrefix(d.Property, "--") {
+		d.Property = strings.ToLower(d.Property)
+	}
+	rest := trimWhitespace(tokens[1:])
+	if len(rest) == 0 || rest[0].Type != ColonToken {
+		return nil, false
+	}
+	value := trimWhitespace(rest[1:])
+	if n := len(value); n >= 2 && value[n-1].Type == IdentToken && strings.EqualFold(value[n-1].Value, "important") {
+		if bang := trimWhitespace(value[:n-1]); len(bang) > 0 && bang[len(bang)-1].Type == DelimToken && bang[len(bang)-1].Value == "!" {
+			d.Important = true
+			value = trimWhitespace(bang[:len(bang)-1])
+		}
+	}
+	d.Value = serialize(value)
+	if d.Value == "" && !strings.HasPrefix(d.Property, "--") {
+		return nil, false
 	}
-	return styles
+	return d, true
 }
@@ -15,8 +15,8 @@ func TestStyleParser(t *testing.T) {
 			"fill:white;stroke:#000000;",
 			utils.Styles(
 				[]*utils.Style{
-					&utils.Style{"fill", "white"},
-					&utils.Style{"stroke", "#000000"},
+					&utils.Style{Property: "fill", Value: "white"},
+					&utils.Style{Property: "stroke", Value: "#000000"},
 				},
 			),
 		},
@@ -24,8 +24,8 @@ func TestStyleParser(t *testing.T) {
 			"fill:white;stroke-opacity:1",
 			utils.Styles(
 				[]*utils.Style{
-					&utils.Style{"fill", "white"},
-					&utils.Style{"stroke-opacity", "1"},
+					&utils.Style{Property: "fill", Value: "white"},
+					&utils.Style{Property: "stroke-opacity", Value: "1"},
 				},
 			),
 		},
@@ -38,3 +38,63 @@ func TestStyleParser(t *testing.T) {
 		}
 	}
 }
+
+func TestStyleParserSyntax(t *testing.T) {
+	testCases := []struct {
+		style      string
+		expected   utils.Styles
+		serialized string
+	}{
+		{
+			"fill: url(data:image/png;base64,AAAA) ; font-family: \"a:b\", serif",
+			utils.Styles{
+				{Property: "fill", Value: "url(data:image/png;base64,AAAA)"},
+				{Property: "font-family", Value: `"a:b", serif`},
+			},
+			`fill:url(data:image/png;base64,AAAA);font-family:"a:b", serif`,
+		},
+		{
+			"/* comment */ STROKE : red ! IMPORTANT; --Custom-Prop: calc(1px; 2px)",
+			utils.Styles{
+				{Property: "stroke", Value: "red", Important: true},
+				{Property: "--Custom-Prop", Value: "calc(1px; 2px)"},
+			},
+			"stroke:red !important;--Custom-Prop:calc(1px; 2px)",
+		},
+		{
+			"[x]: y; 1: 2; fill; @foo {a: b}; stroke-width: 2; opacity:",
+			utils.Styles{
+				{Property: "stroke-width", Value: "2"},
+			},
+			"stroke-width:2",
+		},
+		{
+			`content: "\"}"; fill:rgb(0,0,0)`,
+			utils.Styles{
+				{Property: "content", Value: `"\"}"`},
+				{Property: "fill", Value: "rgb(0,0,0)"},
+			},
+			`content:"\"}";fill:rgb(0,0,0)`,
+		},
+	}
+
+	for _, test := range testCases {
+		actual := utils.StyleParser(test.style)
+		if !test.expected.Compare(actual) {
+			t.Errorf("StyleParser %q: expected %v, actual %v\n", test.style, test.expected, actual)
+		}
+		if s := actual.String(); s != test.serialized {
+			t.Errorf("Styles.String %q: expected %q, actual %q\n", test.style, test.serialized, s)
+		}
+	}
+}
+
+func TestStylesString(t *testing.T) {
+	styles := utils.StyleParser("fill: white; stroke : #000 !important;")
+	if actual := styles.String(); actual != "fill:white;stroke:#000 !important" {
+		t.Errorf("Styles.String: expected %q, actual %q\n", "fill:white;stroke:#000 !important", actual)
+	}
+	if reparsed := utils.StyleParser(styles.String()); !styles.Compare(reparsed) {
+		t.Errorf("Styles.String round trip: expected %v, actual %v\n", styles, reparsed)
+	}
+}
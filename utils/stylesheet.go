@@ -0,0 +1,485 @@
+package utils
+
+import (
+	"strings"
+)
+
+// AttributeSelector matches an attribute of an element. Operator is empty
+// for a presence test or one of "=", "~=", "|=", "^=", "$=" and "*=".
+// Insensitive compares values ignoring ASCII case.
+type AttributeSelector struct {
+	Name        string
+	Operator    string
+	Value       string
+	Insensitive bool
+}
+
+// CompoundSelector is a sequence of simple selectors which all match the
+// same element. Type is empty or "*" for any element. PseudoClasses holds
+// the names of pseudo-classes without arguments, and Not the compound
+// selectors of :not() arguments, one of which must not match.
+type CompoundSelector struct {
+	Type          string
+	IDs           []string
+	Classes       []string
+	Attributes    []AttributeSelector
+	PseudoClasses []string
+	Not           [][]CompoundSelector
+	PseudoElement string
+}
+
+// SelectorPart is a compound selector together with the combinator which
+// relates it to the part before it: " " for descendants, ">" for children,
+// "+" for next siblings and "~" for subsequent siblings. The combinator of
+// the first part is empty.
+type SelectorPart struct {
+	Combinator string
+	Compound   CompoundSelector
+}
+
+// Selector is a complex selector, whose last part selects the subject.
+type Selector struct {
+	Parts []SelectorPart
+	Raw   string
+}
+
+// CSSRule is a style rule of a stylesheet. Media holds the media query
+// lists of the enclosing @media rules and @import rules, which all have to
+// match for the rule to apply.
+type CSSRule struct {
+	Selectors    []Selector
+	Declarations Styles
+	Media        []string
+}
+
+// Stylesheet is a list of style rules in source order.
+type Stylesheet struct {
+	Rules []CSSRule
+}
+
+// ImportLoader returns the source of the stylesheet imported from url.
+type ImportLoader func(url string) (string, error)
+
+// StylesheetParserError contains errors which have occured when parsing a
+// selector.
+type StylesheetParserError struct {
+	msg string
+}
+
+func (err StylesheetParserError) Error() string {
+	return err.msg
+}
+
+// maxImportDepth limits nested @import rules, which may be cyclic.
+const maxImportDepth = 16
+
+// StylesheetParser parses the content of a <style> element following CSS
+// Syntax Level 3. Style rules with invalid selectors are dropped, as are
+// at-rules other than @media and @import. @import rules are refused if
+// loader is nil, and otherwise their stylesheets are loaded and their rules
+// inserted in place; errors of the loader are returned.
+func StylesheetParser(css string, loader ImportLoader) (*Stylesheet, error) {
+	sheet := &Stylesheet{}
+	err := sheet.parseRules(CSSTokenizer(css), nil, loader, true, 0)
+	return sheet, err
+}
+
+// blockEnd returns the index of the token closing the block opened at i.
+func blockEnd(tokens []CSSToken, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case FunctionToken, OpenParenToken, OpenSquareToken, OpenCurlyToken:
+			depth++
+		case CloseParenToken, CloseSquareToken, CloseCurlyToken:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// parseRules appends the rules of a list of rules to the stylesheet.
+func (sheet *Stylesheet) parseRules(tokens []CSSToken, media []string, loader ImportLoader, topLevel bool, depth int) error {
+	importsAllowed := topLevel
+	for i := 0; i < len(tokens); {
+		switch tokens[i].Type {
+		case WhitespaceToken:
+			i++
+			continue
+		case CDOToken, CDCToken:
+			if topLevel {
+				i++
+				continue
+			}
+		case AtKeywordToken:
+			name := strings.ToLower(tokens[i].Value)
+			end := i + 1
+			for end < len(tokens) && tokens[end].Type != SemicolonToken && tokens[end].Type != OpenCurlyToken {
+				if tokens[end].Type == FunctionToken || tokens[end].Type == OpenParenToken || tokens[end].Type == OpenSquareToken {
+					end = blockEnd(tokens, end)
+				}
+				end++
+			}
+			prelude := trimWhitespace(tokens[i+1 : minIndex(end, len(tokens))])
+			var block []CSSToken
+			next := end + 1
+			if end < len(tokens) && tokens[end].Type == OpenCurlyToken {
+				closing := blockEnd(tokens, end)
+				block = tokens[end+1 : minIndex(closing, len(tokens))]
+				next = closing + 1
+			}
+			switch name {
+			case "charset":
+			case "import":
+				if importsAllowed {
+					if err := sheet.parseImport(prelude, media, loader, depth); err != nil {
+						return err
+					}
+				}
+			case "media":
+				importsAllowed = false
+				if err := sheet.parseRules(block, append(media[:len(media):len(media)], serialize(prelude)), loader, false, depth); err != nil {
+					return err
+				}
+			default:
+				importsAllowed = false
+			}
+			i = next
+			continue
+		}
+
+		// qualified rule
+		importsAllowed = false
+		end := i
+		for end < len(tokens) && tokens[end].Type != OpenCurlyToken {
+			if tokens[end].Type == FunctionToken || tokens[end].Type == OpenParenToken || tokens[end].Type == OpenSquareToken {
+				end = blockEnd(tokens, end)
+			}
+			end++
+		}
+		if end >= len(tokens) {
+			return nil
+		}
+		closing := blockEnd(tokens, end)
+		if selectors, err := parseSelectorList(tokens[i:end]); err == nil {
+			sheet.Rules = append(sheet.Rules, CSSRule{
+				Selectors:    selectors,
+				Declarations: parseDeclarations(tokens[end+1 : minIndex(closing, len(tokens))]),
+				Media:        media,
+			})
+		}
+		i = closing + 1
+	}
+	return nil
+}
+
+func minIndex(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// parseImport loads and parses the stylesheet of an @import rule with the
+// given prelude.
+func (sheet *Stylesheet) parseImport(prelude []CSSToken, media []string, loader ImportLoader, depth int) error {
+	if loader == nil || len(prelude) == 0 || depth >= maxImportDepth {
+		return nil
+	}
+	var url string
+	rest := prelude[1:]
+	switch t := prelude[0]; {
+	case t.Type == URLToken || t.Type == StringToken:
+		url = t.Value
+	case t.Type == FunctionToken && strings.EqualFold(t.Value, "url"):
+		args := trimWhitespace(prelude[1:blockEnd(prelude, 0)])
+		if len(args) != 1 || args[0].Type != StringToken {
+			return nil
+		}
+		url = args[0].Value
+		rest = prelude[minIndex(blockEnd(prelude, 0)+1, len(prelude)):]
+	default:
+		return nil
+	}
+	css, err := loader(url)
+	if err != nil {
+		return err
+	}
+	if query := serialize(trimWhitespace(rest)); query != "" {
+		media = append(media[:len(media):len(media)], query)
+	}
+	return sheet.parseRules(CSSTokenizer(css), media, loader, true, depth+1)
+}
+
+// MediaMatches returns true if the media query list matches the medium,
+// such as "screen" or "print". A query matches if its media type is the
+// medium or all, optionally negated with not. Queries with media features
+// cannot be evaluated without a viewport and do not match.
+func MediaMatches(query, medium string) bool {
+	if strings.TrimSpace(query) == "" {
+		return true
+	}
+	for _, q := range strings.Split(query, ",") {
+		fields := strings.Fields(strings.ToLower(q))
+		not := false
+		if len(fields) > 0 && (fields[0] == "not" || fields[0] == "only") {
+			not = fields[0] == "not"
+			fields = fields[1:]
+		}
+		if len(fields) != 1 || strings.HasPrefix(fields[0], "(") {
+			continue
+		}
+		if (fields[0] == "all" || fields[0] == strings.ToLower(medium)) != not {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectorParser parses a comma separated list of selectors.
+func SelectorParser(raw string) ([]Selector, error) {
+	return parseSelectorList(CSSTokenizer(raw))
+}
+
+// parseSelectorList parses the tokens of a comma separated list of
+// selectors. Any invalid selector makes the list invalid.
+func parseSelectorList(tokens []CSSToken) ([]Selector, error) {
+	var selectors []Selector
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && tokens[i].Type != CommaToken {
+			if tokens[i].Type == FunctionToken || tokens[i].Type == OpenSquareToken || tokens[i].Type == OpenParenToken {
+				i = blockEnd(tokens, i)
+			}
+			continue
+		}
+		part := trimWhitespace(tokens[start:minIndex(i, len(tokens))])
+		s, err := parseSelector(part)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+		start = i + 1
+	}
+	return selectors, nil
+}
+
+func selectorError(tokens []CSSToken) error {
+	return StylesheetParserError{"invalid selector " + strings.TrimSpace(serialize(tokens))}
+}
+
+// parseSelector parses the tokens of a complex selector.
+func parseSelector(tokens []CSSToken) (Selector, error) {
+	s := Selector{Raw: serialize(tokens)}
+	combinator := ""
+	for i := 0; i < len(tokens); {
+		compound, next, err := parseCompound(tokens, i)
+		if err != nil {
+			return Selector{}, err
+		}
+		s.Parts = append(s.Parts, SelectorPart{combinator, compound})
+		i = next
+		if i >= len(tokens) {
+			break
+		}
+		combinator = ""
+		for i < len(tokens) && tokens[i].Type == WhitespaceToken {
+			combinator = " "
+			i++
+		}
+		if i < len(tokens) && tokens[i].Type == DelimToken && strings.Contains(">+~", tokens[i].Value) {
+			combinator = tokens[i].Value
+			i++
+			for i < len(tokens) && tokens[i].Type == WhitespaceToken {
+				i++
+			}
+		}
+		if combinator == "" || i >= len(tokens) || compound.PseudoElement != "" {
+			return Selector{}, selectorError(tokens)
+		}
+	}
+	if len(s.Parts) == 0 {
+		return Selector{}, selectorError(tokens)
+	}
+	return s, nil
+}
+
+// parseCompound parses a compound selector starting at i and returns the
+// index after it.
+func parseCompound(tokens []CSSToken, i int) (CompoundSelector, int, error) {
+	var c CompoundSelector
+	start := i
+	if i < len(tokens) && (tokens[i].Type == IdentToken || tokens[i].Type == DelimToken && tokens[i].Value == "*") {
+		c.Type = tokens[i].Value
+		i++
+	}
+	for i < len(tokens) {
+		t := tokens[i]
+		switch {
+		case t.Type == HashToken:
+			c.IDs = append(c.IDs, t.Value)
+			i++
+		case t.Type == DelimToken && t.Value == ".":
+			if i+1 >= len(tokens) || tokens[i+1].Type != IdentToken {
+				return c, i, selectorError(tokens[start:])
+			}
+			c.Classes = append(c.Classes, tokens[i+1].Value)
+			i += 2
+		case t.Type == OpenSquareToken:
+			end := blockEnd(tokens, i)
+			a, err := parseAttributeSelector(tokens[i+1 : minIndex(end, len(tokens))])
+			if err != nil {
+				return c, i, err
+			}
+			c.Attributes = append(c.Attributes, a)
+			i = end + 1
+		case t.Type == ColonToken:
+			next, err := c.parsePseudo(tokens, i)
+			if err != nil {
+				return c, i, err
+			}
+			i = next
+		default:
+			if i == start {
+				return c, i, selectorError(tokens[start:])
+			}
+			return c, i, nil
+		}
+	}
+	if i == start {
+		return c, i, selectorError(tokens[start:])
+	}
+	return c, i, nil
+}
+
+// parsePseudo parses a pseudo-class or pseudo-element starting with the colon
+// at i and returns the index after it.
+func (c *CompoundSelector) parsePseudo(tokens []CSSToken, i int) (int, error) {
+	i++
+	element := i < len(tokens) && tokens[i].Type == ColonToken
+	if element {
+		i++
+	}
+	if i >= len(tokens) {
+		return i, selectorError(tokens)
+	}
+	t := tokens[i]
+	switch {
+	case t.Type == IdentToken && element:
+		c.PseudoElement = strings.ToLower(t.Value)
+		return i + 1, nil
+	case t.Type == IdentToken:
+		name := strings.ToLower(t.Value)
+		if name == "before" || name == "after" || name == "first-line" || name == "first-letter" {
+			c.PseudoElement = name
+		} else {
+			c.PseudoClasses = append(c.PseudoClasses, name)
+		}
+		return i + 1, nil
+	case t.Type == FunctionToken && !element && strings.EqualFold(t.Value, "not"):
+		end := blockEnd(tokens, i)
+		args, err := parseSelectorList(tokens[i+1 : minIndex(end, len(tokens))])
+		if err != nil {
+			return i, err
+		}
+		var not []CompoundSelector
+		for _, s := range args {
+			if len(s.Parts) != 1 {
+				return i, selectorError(tokens)
+			}
+			not = append(not, s.Parts[0].Compound)
+		}
+		c.Not = append(c.Not, not)
+		return end + 1, nil
+	}
+	return i, selectorError(tokens)
+}
+
+// parseAttributeSelector parses the tokens within the brackets of an
+// attribute selector.
+func parseAttributeSelector(tokens []CSSToken) (AttributeSelector, error) {
+	tokens = trimWhitespace(tokens)
+	if len(tokens) == 0 || tokens[0].Type != IdentToken {
+		return AttributeSelector{}, selectorError(tokens)
+	}
+	a := AttributeSelector{Name: tokens[0].Value}
+	rest := trimWhitespace(tokens[1:])
+	if len(rest) == 0 {
+		return a, nil
+	}
+	switch {
+	case rest[0].Type == DelimToken && rest[0].Value == "=":
+		a.Operator, rest = "=", rest[1:]
+	case len(rest) > 1 && rest[0].Type == DelimToken && strings.Contains("~|^$*", rest[0].Value) &&
+		rest[1].Type == DelimToken && rest[1].Value == "=":
+		a.Operator, rest = rest[0].Value+"=", rest[2:]
+	default:
+		return AttributeSelector{}, selectorError(tokens)
+	}
+	rest = trimWhitespace(rest)
+	if len(rest) == 0 || rest[0].Type != IdentToken && rest[0].Type != StringToken {
+		return AttributeSelector{}, selectorError(tokens)
+	}
+	a.Value = rest[0].Value
+	rest = trimWhitespace(rest[1:])
+	if len(rest) == 1 && rest[0].Type == IdentToken && (strings.EqualFold(rest[0].Value, "i") || strings.EqualFold(rest[0].Value, "s")) {
+		a.Insensitive = strings.EqualFold(rest[0].Value, "i")
+		rest = nil
+	}
+	if len(rest) > 0 {
+		return AttributeSelector{}, selectorError(tokens)
+	}
+	return a, nil
+}
+
+// specificity returns the specificity of the compound selector.
+func (c CompoundSelector) specificity() [3]int {
+	s := [3]int{len(c.IDs), len(c.Classes) + len(c.Attributes) + len(c.PseudoClasses), 0}
+	if c.Type != "" && c.Type != "*" {
+		s[2]++
+	}
+	if c.PseudoElement != "" {
+		s[2]++
+	}
+	for _, not := range c.Not {
+		// :not() takes the specificity of its most specific argument
+		var max [3]int
+		for _, n := range not {
+			if ns := n.specificity(); CompareSpecificity(ns, max) > 0 {
+				max = ns
+			}
+		}
+		for i := range s {
+			s[i] += max[i]
+		}
+	}
+	return s
+}
+
+// Specificity returns the specificity of the selector as the numbers of ID
+// selectors, of class, attribute and pseudo-class selectors, and of type
+// selectors and pseudo-elements.
+func (s Selector) Specificity() [3]int {
+	var total [3]int
+	for _, p := range s.Parts {
+		ps := p.Compound.specificity()
+		for i := range total {
+			total[i] += ps[i]
+		}
+	}
+	return total
+}
+
+// CompareSpecificity returns a negative number, zero or a positive number if
+// specificity a is lower than, equal to or higher than b.
+func CompareSpecificity(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
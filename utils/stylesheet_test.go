@@ -0,0 +1,114 @@
+package utils_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestStylesheetParser(t *testing.T) {
+	css := `
+		@charset "utf-8";
+		@import "base.css" print;
+		<!-- rect, .a > #b { fill: red !important; stroke: blue } -->
+		@media screen and (min-width: 10px) { circle { fill: green } }
+		@font-face { font-family: x }
+		p! { fill: red }
+		path::before { fill: none }
+		@import "late.css";
+	`
+	loaded := map[string]string{"base.css": "g { opacity: .5 }", "late.css": "late { fill: red }"}
+	loader := func(url string) (string, error) { return loaded[url], nil }
+	sheet, err := utils.StylesheetParser(css, loader)
+	if err != nil {
+		t.Fatalf("StylesheetParser failed: %v\n", err)
+	}
+
+	expected := []struct {
+		selectors    []string
+		declarations utils.Styles
+		media        []string
+	}{
+		{[]string{"g"}, utils.Styles{{Property: "opacity", Value: ".5"}}, []string{"print"}},
+		{[]string{"rect", ".a > #b"}, utils.Styles{{Property: "fill", Value: "red", Important: true}, {Property: "stroke", Value: "blue"}}, nil},
+		{[]string{"circle"}, utils.Styles{{Property: "fill", Value: "green"}}, []string{"screen and (min-width: 10px)"}},
+		{[]string{"path::before"}, utils.Styles{{Property: "fill", Value: "none"}}, nil},
+	}
+	if len(sheet.Rules) != len(expected) {
+		t.Fatalf("StylesheetParser: expected %d rules, actual %+v\n", len(expected), sheet.Rules)
+	}
+	for i, rule := range sheet.Rules {
+		var selectors []string
+		for _, s := range rule.Selectors {
+			selectors = append(selectors, s.Raw)
+		}
+		if !reflect.DeepEqual(selectors, expected[i].selectors) ||
+			!reflect.DeepEqual(rule.Declarations, expected[i].declarations) ||
+			!reflect.DeepEqual(rule.Media, expected[i].media) {
+			t.Errorf("StylesheetParser rule %d: expected %+v, actual %v %+v %v\n", i, expected[i], selectors, rule.Declarations, rule.Media)
+		}
+	}
+
+	refused, _ := utils.StylesheetParser(`@import url(base.css); g { fill: red }`, nil)
+	if len(refused.Rules) != 1 {
+		t.Errorf("StylesheetParser: expected @import to be refused, actual %+v\n", refused.Rules)
+	}
+	failing := func(url string) (string, error) { return "", errors.New("not found") }
+	if _, err := utils.StylesheetParser(`@import url("a.css");`, failing); err == nil {
+		t.Errorf("StylesheetParser: expected loader error\n")
+	}
+}
+
+func TestSelectorParser(t *testing.T) {
+	testCases := []struct {
+		selector    string
+		specificity [3]int
+	}{
+		{"*", [3]int{0, 0, 0}},
+		{"rect", [3]int{0, 0, 1}},
+		{"g > rect.a.b", [3]int{0, 2, 2}},
+		{"#id [fill=\"red\" i]:first-child", [3]int{1, 2, 0}},
+		{"svg path:not(#x, .y) ~ circle + line", [3]int{1, 0, 4}},
+	}
+
+	for _, test := range testCases {
+		selectors, err := utils.SelectorParser(test.selector)
+		if err != nil || len(selectors) != 1 {
+			t.Errorf("SelectorParser %q failed: %v\n", test.selector, err)
+			continue
+		}
+		if actual := selectors[0].Specificity(); actual != test.specificity {
+			t.Errorf("Specificity %q: expected %v, actual %v\n", test.selector, test.specificity, actual)
+		}
+	}
+
+	for _, raw := range []string{"", "a,", "> a", "a >", ".", "a::before b", "a:not(b c)", "[x~y]"} {
+		if _, err := utils.SelectorParser(raw); err == nil {
+			t.Errorf("SelectorParser %q: expected error\n", raw)
+		}
+	}
+}
+
+func TestMediaMatches(t *testing.T) {
+	testCases := []struct {
+		query    string
+		medium   string
+		expected bool
+	}{
+		{"", "screen", true},
+		{"all", "print", true},
+		{"screen, print", "print", true},
+		{"only screen", "screen", true},
+		{"not print", "screen", true},
+		{"print", "screen", false},
+		{"screen and (min-width: 100px)", "screen", false},
+	}
+
+	for _, test := range testCases {
+		if actual := utils.MediaMatches(test.query, test.medium); actual != test.expected {
+			t.Errorf("MediaMatches %q %q: expected %v, actual %v\n", test.query, test.medium, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// epsilon is the tolerance used when comparing computed coordinates.
+const epsilon = 1e-9
+
+// Point is a position in a two-dimensional coordinate system.
+type Point struct {
+	X, Y float64
+}
+
+// Matrix is a 2D affine transformation matrix as used by the 'transform'
+// attribute. It maps (x, y) to (A*x + C*y + E, B*x + D*y + F).
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// TransformParserError contains errors which have occured when parsing
+// 'transform' attribute.
+type TransformParserError struct {
+	msg string
+}
+
+func (err TransformParserError) Error() string {
+	return err.msg
+}
+
+// Identity returns the identity matrix.
+func Identity() Matrix {
+	return Matrix{1, 0, 0, 1, 0, 0}
+}
+
+// Translate returns a translation matrix.
+func Translate(tx, ty float64) Matrix {
+	return Matrix{1, 0, 0, 1, tx, ty}
+}
+
+// Scale returns a scaling matrix.
+func Scale(sx, sy float64) Matrix {
+	return Matrix{sx, 0, 0, sy, 0, 0}
+}
+
+// Rotate returns a matrix rotating by angle degrees around the origin.
+func Rotate(angle float64) Matrix {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return Matrix{cos, sin, -sin, cos, 0, 0}
+}
+
+// RotateAround returns a matrix rotating by angle degrees around (cx, cy).
+func RotateAround(angle, cx, cy float64) Matrix {
+	return Translate(cx, cy).Multiply(Rotate(angle)).Multiply(Translate(-cx, -cy))
+}
+
+// SkewX returns a matrix skewing along the x-axis by angle degrees.
+func SkewX(angle float64) Matrix {
+	return Matrix{1, 0, math.Tan(angle * math.Pi / 180), 1, 0, 0}
+}
+
+// SkewY returns a matrix skewing along the y-axis by angle degrees.
+func SkewY(angle float64) Matrix {
+	return Matrix{1, math.Tan(angle * math.Pi / 180), 0, 1, 0, 0}
+}
+
+// Multiply returns the product m × o, which applies o first and m second.
+// This corresponds to the transform list "m o".
+func (m Matrix) Multiply(o Matrix) Matrix {
+	return Matrix{
+		m.A*o.A + m.C*o.B,
+		m.B*o.A + m.D*o.B,
+		m.A*o.C + m.C*o.D,
+		m.B*o.C + m.D*o.D,
+		m.A*o.E + m.C*o.F + m.E,
+		m.B*o.E + m.D*o.F + m.F,
+	}
+}
+
+// Determinant returns the determinant of the linear part of the matrix.
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// Invert returns the inverse matrix, or an error if m is not invertible.
+func (m Matrix) Invert() (Matrix, error) {
+	det := m.Determinant()
+	if math.Abs(det) < epsilon*epsilon {
+		return Matrix{}, TransformParserError{"matrix is not invertible"}
+	}
+	return Matrix{
+		m.D / det,
+		-m.B / det,
+		-m.C / det,
+		m.A / det,
+		(m.C*m.F - m.D*m.E) / det,
+		(m.B*m.E - m.A*m.F) / det,
+	}, nil
+}
+
+// Apply transforms the point.
+func (m Matrix) Apply(p Point) Point {
+	return Point{m.A*p.X + m.C*p.Y + m.E, m.B*p.X + m.D*p.Y + m.F}
+}
+
+// ApplyVector transforms the vector, ignoring the translation.
+func (m Matrix) ApplyVector(p Point) Point {
+	return Point{m.A*p.X + m.C*p.Y, m.B*p.X + m.D*p.Y}
+}
+
+// IsIdentity returns true if the matrix is the identity matrix.
+func (m Matrix) IsIdentity() bool {
+	return m.Equal(Identity())
+}
+
+// Equal returns true if both matrices are equal within a small tolerance.
+func (m Matrix) Equal(o Matrix) bool {
+	return m.equalWithin(o, epsilon)
+}
+
+// Decomposition describes a matrix as the transform list
+// "translate(TranslateX TranslateY) rotate(Rotation) skewX(SkewX)
+// scale(ScaleX ScaleY)". Angles are in degrees.
+type Decomposition struct {
+	TranslateX, TranslateY float64
+	Rotation               float64
+	SkewX                  float64
+	ScaleX, ScaleY         float64
+}
+
+// Decompose splits the matrix into translation, rotation, skew and scale.
+func (m Matrix) Decompose() Decomposition {
+	d := Decomposition{TranslateX: m.E, TranslateY: m.F}
+	d.ScaleX = math.Hypot(m.A, m.B)
+	if d.ScaleX == 0 {
+		d.ScaleY = math.Hypot(m.C, m.D)
+		if d.ScaleY != 0 {
+			d.Rotation = math.Atan2(-m.C, m.D) * 180 / math.Pi
+		}
+		return d
+	}
+	d.Rotation = math.Atan2(m.B, m.A) * 180 / math.Pi
+	d.ScaleY = m.Determinant() / d.ScaleX
+	if d.ScaleY != 0 {
+		d.SkewX = math.Atan((m.A*m.C+m.B*m.D)/(d.ScaleX*d.ScaleY)) * 180 / math.Pi
+	}
+	return d
+}
+
+// formatTransformNumber formats a number with at most ten decimals.
+func formatTransformNumber(n float64) string {
+	n = math.Round(n*1e10) / 1e10
+	if n == 0 {
+		n = 0 // avoid "-0"
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func transformFunction(name string, args ...float64) string {
+	s := make([]string, len(args))
+	for i, a := range args {
+		s[i] = formatTransformNumber(a)
+	}
+	return name + "(" + strings.Join(s, " ") + ")"
+}
+
+// String returns the shortest transform list equivalent to the matrix.
+func (m Matrix) String() string {
+	if m.IsIdentity() {
+		return ""
+	}
+	candidates := []string{transformFunction("matrix", m.A, m.B, m.C, m.D, m.E, m.F)}
+	linear := Matrix{m.A, m.B, m.C, m.D, 0, 0}
+	var translate string
+	if m.E != 0 || m.F != 0 {
+		if math.Abs(m.F) < epsilon {
+			translate = transformFunction("translate", m.E)
+		} else {
+			translate = transformFunction("translate", m.E, m.F)
+		}
+	}
+
+	var linears []string
+	d := m.Decompose()
+	if linear.IsIdentity() {
+		linears = append(linears, "")
+	}
+	if math.Abs(m.B) < epsilon && math.Abs(m.C) < epsilon {
+		if math.Abs(m.A-m.D) < epsilon {
+			linears = append(linears, transformFunction("scale", m.A))
+		} else {
+			linears = append(linears, transformFunction("scale", m.A, m.D))
+		}
+	}
+	if math.Abs(d.ScaleX-1) < epsilon && math.Abs(d.ScaleY-1) < epsilon && math.Abs(d.SkewX) < epsilon {
+		linears = append(linears, transformFunction("rotate", d.Rotation))
+		// a rotation around a center absorbs the translation
+		sin, cos := math.Sincos(d.Rotation * math.Pi / 180)
+		det := (1-cos)*(1-cos) + sin*sin
+		if translate != "" && det > epsilon {
+			cx := ((1-cos)*m.E - sin*m.F) / det
+			cy := (sin*m.E + (1-cos)*m.F) / det
+			candidates = append(candidates, transformFunction("rotate", d.Rotation, cx, cy))
+		}
+	}
+	if math.Abs(m.A-1) < epsilon && math.Abs(m.B) < epsilon && math.Abs(m.D-1) < epsilon {
+		linears = append(linears, transformFunction("skewX", math.Atan(m.C)*180/math.Pi))
+	}
+	if math.Abs(m.A-1) < epsilon && math.Abs(m.C) < epsilon && math.Abs(m.D-1) < epsilon {
+		linears = append(linears, transformFunction("skewY", math.Atan(m.B)*180/math.Pi))
+	}
+	for _, l := range linears {
+		candidates = append(candidates, strings.TrimSpace(translate+" "+l))
+	}
+
+	shortest := candidates[0]
+	for _, c := range candidates[1:] {
+		if len(c) < len(shortest) {
+			if parsed, err := TransformParser(c); err == nil && parsed.equalWithin(m, 1e-6) {
+				shortest = c
+			}
+		}
+	}
+	return shortest
+}
+
+func (m Matrix) equalWithin(o Matrix, tolerance float64) bool {
+	return math.Abs(m.A-o.A) < tolerance && math.Abs(m.B-o.B) < tolerance &&
+		math.Abs(m.C-o.C) < tolerance && math.Abs(m.D-o.D) < tolerance &&
+		math.Abs(m.E-o.E) < tolerance && math.Abs(m.F-o.F) < tolerance
+}
+
+var transformArguments = map[string][]int{
+	"matrix":    {6},
+	"translate": {1, 2},
+	"scale":     {1, 2},
+	"rotate":    {1, 3},
+	"skewX":     {1},
+	"skewY":     {1},
+}
+
+func isTransformSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+// transformParameters reads the parameters of a transform with the number
+// scanner of the path parser. Numbers are separated by whitespace with at
+// most one comma, or by nothing where the grammar allows, as in "10-5" or
+// ".5.5".
+func transformParameters(name, raw string) ([]float64, error) {
+	var args []float64
+	i := 0
+	skip := func() {
+		for i < len(raw) && isWhitespace(raw[i]) {
+			i++
+		}
+	}
+	skip()
+	for i < len(raw) {
+		if len(args) > 0 && raw[i] == ',' {
+			i++
+			skip()
+		}
+		length := scanNumber(raw[i:])
+		if length == 0 {
+			return nil, TransformParserError{"Incorrect parameter " + strings.TrimSpace(raw[i:]) + " for " + name}
+		}
+		n, err := strconv.ParseFloat(raw[i:i+length], 64)
+		if err != nil {
+			return nil, TransformParserError{"Incorrect parameter " + raw[i:i+length] + " for " + name}
+		}
+		args = append(args, n)
+		i += length
+		skip()
+	}
+	return args, nil
+}
+
+// TransformParser takes value of a 'transform' attribute and transforms it to
+// the equivalent matrix.
+func TransformParser(raw string) (Matrix, error) {
+	m := Identity()
+	rest := strings.TrimLeftFunc(raw, isTransformSeparator)
+	for rest != "" {
+		open := strings.IndexByte(rest, '(')
+		closing := strings.IndexByte(rest, ')')
+		if open < 0 || closing < open {
+			return Matrix{}, TransformParserError{"Incorrect transform list " + raw}
+		}
+		name := strings.TrimSpace(rest[:open])
+		expected, ok := transformArguments[name]
+		if !ok {
+			return Matrix{}, TransformParserError{"Unknown transform " + name}
+		}
+		args, err := transformParameters(name, rest[open+1:closing])
+		if err != nil {
+			return Matrix{}, err
+		}
+		valid := false
+		for _, n := range expected {
+			valid = valid || n == len(args)
+		}
+		if !valid {
+			return Matrix{}, TransformParserError{"Incorrect number of parameters for " + name}
+		}
+
+		var t Matrix
+		switch name {
+		case "matrix":
+			t = Matrix{args[0], args[1], args[2], args[3], args[4], args[5]}
+		case "translate":
+			args = append(args, 0)
+			t = Translate(args[0], args[1])
+		case "scale":
+			args = append(args, args[0])
+			t = Scale(args[0], args[1])
+		case "rotate":
+			if len(args) == 3 {
+				t = RotateAround(args[0], args[1], args[2])
+			} else {
+				t = Rotate(args[0])
+			}
+		case "skewX":
+			t = SkewX(args[0])
+		case "skewY":
+			t = SkewY(args[0])
+		}
+		m = m.Multiply(t)
+		rest = strings.TrimLeftFunc(rest[closing+1:], isTransformSeparator)
+	}
+	return m, nil
+}
@@ -0,0 +1,102 @@
+package utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestTransformParser(t *testing.T) {
+	var testCases = []struct {
+		transform string
+		expected  utils.Matrix
+	}{
+		{"", utils.Identity()},
+		{"translate(10)", utils.Matrix{1, 0, 0, 1, 10, 0}},
+		{"translate(10,-20) scale(2)", utils.Matrix{2, 0, 0, 2, 10, -20}},
+		{"scale(2 3)", utils.Matrix{2, 0, 0, 3, 0, 0}},
+		{"rotate(90)", utils.Matrix{0, 1, -1, 0, 0, 0}},
+		{"rotate(90 10 10)", utils.Matrix{0, 1, -1, 0, 20, 0}},
+		{"skewX(45)", utils.Matrix{1, 0, 1, 1, 0, 0}},
+		{"skewY(45)", utils.Matrix{1, 1, 0, 1, 0, 0}},
+		{"matrix(1,2,3,4,5,6)", utils.Matrix{1, 2, 3, 4, 5, 6}},
+		{" translate(1e1, 0) ,rotate(180) ", utils.Matrix{-1, 0, 0, -1, 10, 0}},
+		{"translate(10-5)", utils.Matrix{1, 0, 0, 1, 10, -5}},
+		{"scale(.5.5)", utils.Matrix{.5, 0, 0, .5, 0, 0}},
+		{"matrix(1 0 0 1 1E1-2e0)", utils.Matrix{1, 0, 0, 1, 10, -2}},
+		{"translate( 1 ,2 )", utils.Matrix{1, 0, 0, 1, 1, 2}},
+	}
+
+	for _, test := range testCases {
+		m, err := utils.TransformParser(test.transform)
+		if !(err == nil && m.Equal(test.expected)) {
+			t.Errorf("Transform: expected %v, actual %v (%v)\n", test.expected, m, err)
+		}
+	}
+}
+
+func TestTransformParserErrors(t *testing.T) {
+	for _, transform := range []string{"translate(1,2,3)", "spin(10)", "scale(1", "rotate(a)", "translate(1,,2)", "translate(1,)", "translate(,1)", "scale(1e)"} {
+		if _, err := utils.TransformParser(transform); err == nil {
+			t.Errorf("Transform: expected error for %v\n", transform)
+		}
+	}
+}
+
+func TestMatrixInvert(t *testing.T) {
+	m, _ := utils.TransformParser("translate(10 20) rotate(30) scale(2 3) skewX(10)")
+	inverse, err := m.Invert()
+	if err != nil || !m.Multiply(inverse).IsIdentity() {
+		t.Errorf("Invert: expected identity, actual %v (%v)\n", m.Multiply(inverse), err)
+	}
+
+	p := m.Apply(utils.Point{3, 4})
+	if q := inverse.Apply(p); math.Abs(q.X-3) > 1e-9 || math.Abs(q.Y-4) > 1e-9 {
+		t.Errorf("Apply: expected %v, actual %v\n", utils.Point{3, 4}, q)
+	}
+
+	if _, err := utils.Scale(0, 1).Invert(); err == nil {
+		t.Error("Invert: expected error for singular matrix")
+	}
+}
+
+func TestMatrixDecompose(t *testing.T) {
+	m, _ := utils.TransformParser("translate(10 20) rotate(30) skewX(10) scale(2 3)")
+	d := m.Decompose()
+	expected := utils.Decomposition{TranslateX: 10, TranslateY: 20, Rotation: 30, SkewX: 10, ScaleX: 2, ScaleY: 3}
+	for _, pair := range [][2]float64{
+		{d.TranslateX, expected.TranslateX}, {d.TranslateY, expected.TranslateY},
+		{d.Rotation, expected.Rotation}, {d.SkewX, expected.SkewX},
+		{d.ScaleX, expected.ScaleX}, {d.ScaleY, expected.ScaleY},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Errorf("Decompose: expected %+v, actual %+v\n", expected, d)
+			break
+		}
+	}
+}
+
+func TestMatrixString(t *testing.T) {
+	var testCases = []struct {
+		transform string
+		expected  string
+	}{
+		{"scale(1)", ""},
+		{"translate(10 0)", "translate(10)"},
+		{"matrix(2 0 0 2 0 0)", "scale(2)"},
+		{"scale(2) translate(5 5)", "matrix(2 0 0 2 10 10)"},
+		{"translate(5 5) rotate(45)", "translate(5 5) rotate(45)"},
+		{"rotate(30)", "rotate(30)"},
+		{"rotate(90 10 10)", "rotate(90 10 10)"},
+		{"skewX(30)", "skewX(30)"},
+		{"rotate(30) scale(2 3)", "matrix(1.7320508076 1 -1.5 2.5980762114 0 0)"},
+	}
+
+	for _, test := range testCases {
+		m, _ := utils.TransformParser(test.transform)
+		if s := m.String(); s != test.expected {
+			t.Errorf("String: expected %q, actual %q\n", test.expected, s)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package utils_test
+
+import (
+	"math"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// closePaths compares two paths allowing for rounding errors.
+func closePaths(expected, actual *utils.Path) bool {
+	if len(expected.Subpaths) != len(actual.Subpaths) {
+		return false
+	}
+	for i, s := range expected.Subpaths {
+		if len(s.Commands) != len(actual.Subpaths[i].Commands) {
+			return false
+		}
+		for j, c := range s.Commands {
+			o := actual.Subpaths[i].Commands[j]
+			if c.Symbol != o.Symbol || len(c.Params) != len(o.Params) {
+				return false
+			}
+			for k, p := range c.Params {
+				if math.Abs(p-o.Params[k]) > 1e-6 {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ViewBox is the rectangle in user space which is mapped to the bounds of a
+// viewport, as given by the 'viewBox' attribute.
+type ViewBox struct {
+	X, Y, Width, Height float64
+}
+
+// PreserveAspectRatio describes how a viewBox is fitted into a viewport of a
+// different aspect ratio, as given by the 'preserveAspectRatio' attribute.
+// Align is "none" or one of "xMinYMin" to "xMaxYMax", and Slice selects
+// slice over meet.
+type PreserveAspectRatio struct {
+	Defer bool
+	Align string
+	Slice bool
+}
+
+// ViewBoxParserError contains errors which have occured when parsing a
+// viewBox or preserveAspectRatio.
+type ViewBoxParserError struct {
+	msg string
+}
+
+func (err ViewBoxParserError) Error() string {
+	return err.msg
+}
+
+// aligns lists the align values of preserveAspectRatio with the fractions of
+// the free space placed before the viewBox horizontally and vertically.
+var aligns = map[string][2]float64{
+	"xMinYMin": {0, 0}, "xMidYMin": {0.5, 0}, "xMaxYMin": {1, 0},
+	"xMinYMid": {0, 0.5}, "xMidYMid": {0.5, 0.5}, "xMaxYMid": {1, 0.5},
+	"xMinYMax": {0, 1}, "xMidYMax": {0.5, 1}, "xMaxYMax": {1, 1},
+}
+
+// ViewBoxParser parses the value of a viewBox attribute: four numbers
+// separated like those of NumberListParser, with a positive width and
+// height.
+func ViewBoxParser(raw string) (ViewBox, error) {
+	n, err := NumberListParser(raw)
+	if err != nil {
+		return ViewBox{}, ViewBoxParserError{"invalid viewBox " + strconv.Quote(raw)}
+	}
+	if len(n) != 4 {
+		return ViewBox{}, ViewBoxParserError{"viewBox must have four numbers: " + strconv.Quote(raw)}
+	}
+	if n[2] <= 0 || n[3] <= 0 {
+		return ViewBox{}, ViewBoxParserError{"viewBox must have a positive size: " + strconv.Quote(raw)}
+	}
+	return ViewBox{n[0], n[1], n[2], n[3]}, nil
+}
+
+// String returns the viewBox as attribute value.
+func (vb ViewBox) String() string {
+	values := make([]string, 4)
+	for i, v := range []float64{vb.X, vb.Y, vb.Width, vb.Height} {
+		values[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(values, " ")
+}
+
+// DefaultPreserveAspectRatio is the initial value xMidYMid meet.
+var DefaultPreserveAspectRatio = PreserveAspectRatio{Align: "xMidYMid"}
+
+// PreserveAspectRatioParser parses the value of a preserveAspectRatio
+// attribute. An empty value gives the initial value xMidYMid meet.
+func PreserveAspectRatioParser(raw string) (PreserveAspectRatio, error) {
+	fields := strings.Fields(raw)
+	p := DefaultPreserveAspectRatio
+	if len(fields) > 0 && fields[0] == "defer" {
+		p.Defer, fields = true, fields[1:]
+	}
+	if len(fields) == 0 {
+		if p.Defer {
+			return PreserveAspectRatio{}, ViewBoxParserError{"missing align in preserveAspectRatio " + strconv.Quote(raw)}
+		}
+		return p, nil
+	}
+	if _, ok := aligns[fields[0]]; !ok && fields[0] != "none" {
+		return PreserveAspectRatio{}, ViewBoxParserError{"invalid align in preserveAspectRatio " + strconv.Quote(raw)}
+	}
+	p.Align = fields[0]
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "meet":
+		case "slice":
+			p.Slice = true
+		default:
+			return PreserveAspectRatio{}, ViewBoxParserError{"invalid meetOrSlice in preserveAspectRatio " + strconv.Quote(raw)}
+		}
+	}
+	if len(fields) > 2 {
+		return PreserveAspectRatio{}, ViewBoxParserError{"invalid preserveAspectRatio " + strconv.Quote(raw)}
+	}
+	return p, nil
+}
+
+// String returns the preserveAspectRatio as attribute value.
+func (p PreserveAspectRatio) String() string {
+	s := p.Align
+	if s == "" {
+		s = DefaultPreserveAspectRatio.Align
+	}
+	if p.Defer {
+		s = "defer " + s
+	}
+	if p.Slice {
+		s += " slice"
+	}
+	return s
+}
+
+// Transform returns the transform which maps the user space of the viewBox
+// to a viewport at (x, y) of the given width and height. With align none the
+// viewBox is stretched to the viewport; otherwise it is scaled uniformly to
+// fit inside the viewport (meet) or to cover it (slice) and aligned within
+// it.
+func (p PreserveAspectRatio) Transform(vb ViewBox, x, y, width, height float64) Matrix {
+	sx, sy := width/vb.Width, height/vb.Height
+	if p.Align == "none" {
+		return Matrix{A: sx, D: sy, E: x - vb.X*sx, F: y - vb.Y*sy}
+	}
+	align, ok := aligns[p.Align]
+	if !ok {
+		align = aligns[DefaultPreserveAspectRatio.Align]
+	}
+	s := math.Min(sx, sy)
+	if p.Slice {
+		s = math.Max(sx, sy)
+	}
+	tx := x - vb.X*s + (width-vb.Width*s)*align[0]
+	ty := y - vb.Y*s + (height-vb.Height*s)*align[1]
+	return Matrix{A: s, D: s, E: tx, F: ty}
+}
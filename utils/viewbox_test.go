@@ -0,0 +1,75 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestViewBoxParser(t *testing.T) {
+	vb, err := utils.ViewBoxParser(" -10,0 200.5\t100 ")
+	expected := utils.ViewBox{X: -10, Y: 0, Width: 200.5, Height: 100}
+	if err != nil || vb != expected {
+		t.Errorf("ViewBoxParser: expected %v, actual %v (%v)\n", expected, vb, err)
+	}
+	if actual := vb.String(); actual != "-10 0 200.5 100" {
+		t.Errorf("ViewBox.String: expected %q, actual %q\n", "-10 0 200.5 100", actual)
+	}
+
+	for _, raw := range []string{"", "0 0 10", "0 0 10 a", "0 0 0 10", "0 0 10 -1"} {
+		if _, err := utils.ViewBoxParser(raw); err == nil {
+			t.Errorf("ViewBoxParser %q: expected error\n", raw)
+		}
+	}
+}
+
+func TestPreserveAspectRatioParser(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected utils.PreserveAspectRatio
+		str      string
+	}{
+		{"", utils.PreserveAspectRatio{Align: "xMidYMid"}, "xMidYMid"},
+		{"none", utils.PreserveAspectRatio{Align: "none"}, "none"},
+		{"xMinYMax slice", utils.PreserveAspectRatio{Align: "xMinYMax", Slice: true}, "xMinYMax slice"},
+		{"defer xMaxYMin meet", utils.PreserveAspectRatio{Defer: true, Align: "xMaxYMin"}, "defer xMaxYMin"},
+	}
+
+	for _, test := range testCases {
+		actual, err := utils.PreserveAspectRatioParser(test.raw)
+		if err != nil || actual != test.expected {
+			t.Errorf("PreserveAspectRatioParser %q: expected %v, actual %v (%v)\n", test.raw, test.expected, actual, err)
+		}
+		if s := actual.String(); s != test.str {
+			t.Errorf("PreserveAspectRatio.String %q: expected %q, actual %q\n", test.raw, test.str, s)
+		}
+	}
+
+	for _, raw := range []string{"xminymin", "defer", "xMidYMid cover", "none meet extra"} {
+		if _, err := utils.PreserveAspectRatioParser(raw); err == nil {
+			t.Errorf("PreserveAspectRatioParser %q: expected error\n", raw)
+		}
+	}
+}
+
+func TestPreserveAspectRatioTransform(t *testing.T) {
+	vb := utils.ViewBox{X: 10, Y: 10, Width: 100, Height: 50}
+	testCases := []struct {
+		raw      string
+		expected utils.Matrix
+	}{
+		{"none", utils.Matrix{A: 2, D: 4, E: -20, F: -40}},
+		{"xMinYMin", utils.Matrix{A: 2, D: 2, E: -20, F: -20}},
+		{"xMidYMid", utils.Matrix{A: 2, D: 2, E: -20, F: 30}},
+		{"xMaxYMax", utils.Matrix{A: 2, D: 2, E: -20, F: 80}},
+		{"xMidYMid slice", utils.Matrix{A: 4, D: 4, E: -140, F: -40}},
+		{"xMaxYMin slice", utils.Matrix{A: 4, D: 4, E: -240, F: -40}},
+	}
+
+	for _, test := range testCases {
+		p, _ := utils.PreserveAspectRatioParser(test.raw)
+		if actual := p.Transform(vb, 0, 0, 200, 200); !actual.Equal(test.expected) {
+			t.Errorf("Transform %q: expected %v, actual %v\n", test.raw, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package svgparser
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+// parseNumber parses a number, optionally followed by the px unit.
+func parseNumber(raw string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(raw), "px"), 64)
+}
+
+// attributeLength returns the value of a length attribute in user units at
+// 96 DPI, or def if it is missing or invalid, like renderers do.
+func (e *Element) attributeLength(r *styleResolver, name string, def float64) float64 {
+	if _, ok := e.Attributes[name]; !ok {
+		return def
+	}
+	l, err := e.length(name, true)
+	if err != nil {
+		return def
+	}
+	return l.Resolve(e.lengthContext(r, 0), lengthDirection(name))
+}
+
+// viewBoxElements lists the elements which take the viewBox and
+// preserveAspectRatio attributes.
+var viewBoxElements = map[string]bool{
+	"svg": true, "symbol": true, "image": true, "marker": true, "pattern": true, "view": true,
+}
+
+// ViewBox returns the parsed viewBox attribute of the element, or nil if it
+// is missing. Invalid values and elements which do not take a viewBox give
+// an error.
+func (e *Element) ViewBox() (*utils.ViewBox, error) {
+	if !viewBoxElements[e.Name] {
+		return nil, ValidationError{e.Name + " has no viewBox"}
+	}
+	raw, ok := e.Attributes["viewBox"]
+	if !ok {
+		return nil, nil
+	}
+	vb, err := utils.ViewBoxParser(raw)
+	if err != nil {
+		return nil, ValidationError{e.Name + ": " + err.Error()}
+	}
+	return &vb, nil
+}
+
+// PreserveAspectRatio returns the parsed preserveAspectRatio attribute of
+// the element, which is xMidYMid meet if it is missing.
+func (e *Element) PreserveAspectRatio() (utils.PreserveAspectRatio, error) {
+	if !viewBoxElements[e.Name] {
+		return utils.PreserveAspectRatio{}, ValidationError{e.Name + " has no preserveAspectRatio"}
+	}
+	p, err := utils.PreserveAspectRatioParser(e.Attributes["preserveAspectRatio"])
+	if err != nil {
+		return utils.PreserveAspectRatio{}, ValidationError{e.Name + ": " + err.Error()}
+	}
+	return p, nil
+}
+
+// viewBox returns the element's viewBox, or false if it is missing or
+// invalid.
+func (e *Element) viewBox() (utils.ViewBox, bool) {
+	vb, err := e.ViewBox()
+	if err != nil || vb == nil {
+		return utils.ViewBox{}, false
+	}
+	return *vb, true
+}
+
+// viewportLength returns a length attribute which sizes or positions the
+// viewport of the element in user units, or def if it is missing, invalid
+// or a percentage without a viewport to refer to.
+func (e *Element) viewportLength(r *styleResolver, name string, def float64) float64 {
+	l, err := e.length(name, true)
+	if _, ok := e.Attributes[name]; !ok || err != nil {
+		return def
+	}
+	c := e.lengthContext(r, 0)
+	if l.Unit == "%" && c.ViewportWidth == 0 && c.ViewportHeight == 0 {
+		return def
+	}
+	return l.Resolve(c, lengthDirection(name))
+}
+
+// viewport returns the position and size of the viewport which the element
+// establishes, in the user space of its parent. Missing sizes default to
+// those of the viewBox, and the position of the root <svg> is ignored.
+func (e *Element) viewport(r *styleResolver, vb utils.ViewBox) (x, y, width, height float64) {
+	switch e.Name {
+	case "marker":
+		return 0, 0, e.viewportLength(r, "markerWidth", 3), e.viewportLength(r, "markerHeight", 3)
+	case "symbol", "pattern":
+	default:
+		if e.Name != "svg" || e.Parent != nil {
+			x, y = e.viewportLength(r, "x", 0), e.viewportLength(r, "y", 0)
+		}
+	}
+	return x, y, e.viewportLength(r, "width", vb.Width), e.viewportLength(r, "height", vb.Height)
+}
+
+// ViewBoxTransform returns the transform which maps the user space of the
+// element's viewBox to its viewport according to preserveAspectRatio, as
+// established by <svg>, <symbol>, <image>, <marker> and <pattern> elements.
+// The viewport is positioned at x and y for nested <svg> and <image>
+// elements; the refX and refY of markers are not included. The identity is
+// returned if the element has no viewBox.
+func (e *Element) ViewBoxTransform() (utils.Matrix, error) {
+	return e.viewBoxTransform(e.defaultStyles())
+}
+
+// viewBoxTransform returns the ViewBoxTransform of the element with font
+// sizes taken from the resolver.
+func (e *Element) viewBoxTransform(r *styleResolver) (utils.Matrix, error) {
+	vb, err := e.ViewBox()
+	if err != nil || vb == nil {
+		return utils.Identity(), err
+	}
+	p, err := e.PreserveAspectRatio()
+	if err != nil {
+		return utils.Identity(), err
+	}
+	x, y, w, h := e.viewport(r, *vb)
+	return p.Transform(*vb, x, y, w, h), nil
+}
+
+// Resize sets the width and height of a root <svg> element to the largest
+// size within the given width and height which keeps its aspect ratio. A
+// viewBox is added if it is missing, so that the content scales with the
+// document.
+func (e *Element) Resize(width, height float64) error {
+	if e.Name != "svg" {
+		return ValidationError{e.Name + " is not an svg"}
+	}
+	vb, err := e.ViewBox()
+	if err != nil {
+		return err
+	}
+	def := utils.ViewBox{}
+	if vb != nil {
+		def = *vb
+	}
+	_, _, w, h := e.viewport(e.defaultStyles(), def)
+	if w <= 0 || h <= 0 {
+		return ValidationError{"svg has no size to resize"}
+	}
+	if vb == nil {
+		e.Attributes["viewBox"] = utils.ViewBox{Width: w, Height: h}.String()
+	}
+	s := math.Min(width/w, height/h)
+	e.Attributes["width"] = formatNumber(w * s)
+	e.Attributes["height"] = formatNumber(h * s)
+	return nil
+}
+
+// FitViewBox sets the viewBox of an <svg> element to the visual bounding box
+// of its content including strokes and markers, which removes any margin
+// around it. Content without bounds gives an error.
+func (e *Element) FitViewBox() error {
+	if e.Name != "svg" {
+		return ValidationError{e.Name + " is not an svg"}
+	}
+	b, err := BBox(e, VisualBox)
+	if err != nil {
+		return err
+	}
+	if b.Width() <= 0 || b.Height() <= 0 {
+		return ValidationError{"svg has no content to fit"}
+	}
+	e.Attributes["viewBox"] = utils.ViewBox{X: b.Min.X, Y: b.Min.Y, Width: b.Width(), Height: b.Height()}.String()
+	return nil
+}
@@ -0,0 +1,108 @@
+package svgparser_test
+
+import (
+	"testing"
+
+	"github.com/chikamim/svgparser/utils"
+)
+
+func TestViewBoxTransform(t *testing.T) {
+	svg := `
+		<svg width="200" height="100" viewBox="0 0 100 100" preserveAspectRatio="xMinYMid slice">
+			<svg id="nested" x="10" y="20" width="50" height="50" viewBox="0 0 10 20" preserveAspectRatio="none" />
+			<marker id="marker" viewBox="0 0 10 10" markerWidth="6" />
+			<image id="image" x="5" y="5" width="10" height="10" viewBox="0 0 20 10" preserveAspectRatio="xMidYMax" />
+			<g id="g" />
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	testCases := []struct {
+		element  string
+		expected utils.Matrix
+	}{
+		{"", utils.Matrix{A: 2, D: 2, E: 0, F: -50}},
+		{"nested", utils.Matrix{A: 5, D: 2.5, E: 10, F: 20}},
+		{"marker", utils.Matrix{A: 0.3, D: 0.3, E: 1.5, F: 0}},
+		{"image", utils.Matrix{A: 0.5, D: 0.5, E: 5, F: 10}},
+	}
+
+	for _, test := range testCases {
+		e := element
+		if test.element != "" {
+			e = element.FindID(test.element)
+		}
+		actual, err := e.ViewBoxTransform()
+		if err != nil {
+			t.Errorf("ViewBoxTransform %s failed: %v\n", test.element, err)
+		} else if !actual.Equal(test.expected) {
+			t.Errorf("ViewBoxTransform %s: expected %v, actual %v\n", test.element, test.expected, actual)
+		}
+	}
+
+	if _, err := element.FindID("g").ViewBoxTransform(); err == nil {
+		t.Errorf("ViewBoxTransform g: expected error\n")
+	}
+	element.Attributes["preserveAspectRatio"] = "xMidYMid fill"
+	if _, err := element.ViewBoxTransform(); err == nil {
+		t.Errorf("ViewBoxTransform: expected error for %q\n", element.Attributes["preserveAspectRatio"])
+	}
+}
+
+func TestResize(t *testing.T) {
+	testCases := []struct {
+		svg             string
+		width, height   float64
+		expectedWidth   string
+		expectedHeight  string
+		expectedViewBox string
+	}{
+		{`<svg width="200" height="100" viewBox="0 0 20 10"></svg>`, 100, 100, "100", "50", "0 0 20 10"},
+		{`<svg width="40" height="80"></svg>`, 100, 100, "50", "100", "0 0 40 80"},
+		{`<svg viewBox="10 10 30 10"></svg>`, 300, 300, "300", "100", "10 10 30 10"},
+	}
+
+	for _, test := range testCases {
+		element, _ := parse(test.svg, false)
+		if err := element.Resize(test.width, test.height); err != nil {
+			t.Errorf("Resize %s failed: %v\n", test.svg, err)
+			continue
+		}
+		actual := []string{element.Attributes["width"], element.Attributes["height"], element.Attributes["viewBox"]}
+		expected := []string{test.expectedWidth, test.expectedHeight, test.expectedViewBox}
+		for i := range actual {
+			if actual[i] != expected[i] {
+				t.Errorf("Resize %s: expected %v, actual %v\n", test.svg, expected, actual)
+				break
+			}
+		}
+	}
+
+	element, _ := parse(`<svg></svg>`, false)
+	if err := element.Resize(100, 100); err == nil {
+		t.Errorf("Resize: expected error for svg without size\n")
+	}
+}
+
+func TestFitViewBox(t *testing.T) {
+	svg := `
+		<svg width="100" height="100" viewBox="0 0 100 100">
+			<defs><rect width="500" height="500" /></defs>
+			<g transform="translate(10 20)">
+				<rect x="5" y="5" width="10" height="20" />
+				<circle cx="40" cy="10" r="5" />
+			</g>
+		</svg>
+	`
+	element, _ := parse(svg, false)
+	if err := element.FitViewBox(); err != nil {
+		t.Fatalf("FitViewBox failed: %v\n", err)
+	}
+	if actual := element.Attributes["viewBox"]; actual != "15 25 40 20" {
+		t.Errorf("FitViewBox: expected %q, actual %q\n", "15 25 40 20", actual)
+	}
+
+	empty, _ := parse(`<svg width="10" height="10"><g /></svg>`, false)
+	if err := empty.FitViewBox(); err == nil {
+		t.Errorf("FitViewBox: expected error for empty svg\n")
+	}
+}